@@ -0,0 +1,79 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// Interface IDs of the WUA callback interfaces implemented by this package.
+var (
+	iidDownloadProgressChangedCallback     = ole.NewGUID("{8C3F1CDD-6173-4591-AEBD-A56A53CA77C1}")
+	iidDownloadCompletedCallback           = ole.NewGUID("{77254866-9F5B-4C8E-B9E2-C77A8530D64B}")
+	iidInstallationProgressChangedCallback = ole.NewGUID("{E01402D5-F8DA-43BA-A012-38894BD048F1}")
+	iidInstallationCompletedCallback       = ole.NewGUID("{45F4F6F3-D602-4F98-9A8A-3EFA152AD2D3}")
+	iidSearchCompletedCallback             = ole.NewGUID("{88AEE058-D4B0-4725-A2F1-814A67AE964C}")
+)
+
+// releaseCallback releases the reference held on a callback created by newCallback.
+func releaseCallback(callback *ole.IDispatch) {
+	if callback != nil {
+		callback.Release()
+	}
+}
+
+// jobPollInterval is how often a waiting job is checked for completion or cancellation.
+const jobPollInterval = 100 * time.Millisecond
+
+// waitForJob blocks until the asynchronous job represented by jobDisp completes.
+// While waiting it pumps the messages of the calling thread, so callbacks of a job
+// started from a single-threaded apartment are delivered on that same thread.
+// If ctx is done before the job completes, RequestAbort is called on the job and
+// ctx.Err() is returned once the job has finished aborting.
+func waitForJob(ctx context.Context, jobDisp *ole.IDispatch) error {
+	aborted := false
+	for {
+		pumpMessages()
+
+		completed, err := toBoolErr(oleutil.GetProperty(jobDisp, "IsCompleted"))
+		if err != nil {
+			return err
+		}
+		if completed {
+			break
+		}
+
+		if !aborted {
+			select {
+			case <-ctx.Done():
+				if _, err := oleutil.CallMethod(jobDisp, "RequestAbort"); err != nil {
+					return wrapError(err)
+				}
+				aborted = true
+			default:
+			}
+		}
+
+		time.Sleep(jobPollInterval)
+	}
+
+	if aborted {
+		return ctx.Err()
+	}
+	return nil
+}
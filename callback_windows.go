@@ -0,0 +1,143 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/go-ole/go-ole"
+)
+
+var (
+	modUser32            = syscall.NewLazyDLL("user32.dll")
+	procPeekMessageW     = modUser32.NewProc("PeekMessageW")
+	procTranslateMessage = modUser32.NewProc("TranslateMessage")
+	procDispatchMessageW = modUser32.NewProc("DispatchMessageW")
+)
+
+const pmRemove = 0x0001
+
+// msg mirrors the native MSG structure. ole.Msg uses 32-bit handles and
+// cannot be passed to PeekMessageW on 64-bit Windows.
+type msg struct {
+	hwnd     uintptr
+	message  uint32
+	wParam   uintptr
+	lParam   uintptr
+	time     uint32
+	pt       ole.Point
+	lPrivate uint32
+}
+
+// pumpMessages dispatches all messages pending on the calling thread's queue.
+// COM delivers calls into a single-threaded apartment through this queue.
+func pumpMessages() {
+	var m msg
+	for {
+		ret, _, _ := procPeekMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0, pmRemove)
+		if ret == 0 {
+			return
+		}
+		procTranslateMessage.Call(uintptr(unsafe.Pointer(&m)))
+		procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+	}
+}
+
+// callback is a COM object implementing one of the WUA callback interfaces,
+// e.g. IDownloadProgressChangedCallback. All of them derive from IUnknown and
+// add a single Invoke(job, callbackArgs) method, so they share one vtable.
+type callback struct {
+	lpVtbl *callbackVtbl
+	ref    int32
+	iid    *ole.GUID
+	invoke func(job *ole.IDispatch, args *ole.IDispatch)
+}
+
+type callbackVtbl struct {
+	pQueryInterface uintptr
+	pAddRef         uintptr
+	pRelease        uintptr
+	pInvoke         uintptr
+}
+
+var (
+	callbackVtable = &callbackVtbl{
+		pQueryInterface: syscall.NewCallback(callbackQueryInterface),
+		pAddRef:         syscall.NewCallback(callbackAddRef),
+		pRelease:        syscall.NewCallback(callbackRelease),
+		pInvoke:         syscall.NewCallback(callbackInvoke),
+	}
+
+	// liveCallbacks keeps callbacks referenced by COM reachable for the garbage collector.
+	liveCallbacks   = map[*callback]struct{}{}
+	liveCallbacksMu sync.Mutex
+)
+
+// newCallback returns a callback object for the callback interface iid which calls invoke.
+// The returned object holds one reference that the caller must release.
+func newCallback(iid *ole.GUID, invoke func(job *ole.IDispatch, args *ole.IDispatch)) *ole.IDispatch {
+	cb := &callback{
+		lpVtbl: callbackVtable,
+		ref:    1,
+		iid:    iid,
+		invoke: invoke,
+	}
+
+	liveCallbacksMu.Lock()
+	liveCallbacks[cb] = struct{}{}
+	liveCallbacksMu.Unlock()
+
+	// The callback is handed to WUA as a VT_DISPATCH argument, which is converted to
+	// the callback interface through QueryInterface.
+	return (*ole.IDispatch)(unsafe.Pointer(cb))
+}
+
+func callbackQueryInterface(this *callback, iid *ole.GUID, punk **ole.IUnknown) uintptr {
+	*punk = nil
+	if ole.IsEqualGUID(iid, ole.IID_IUnknown) || ole.IsEqualGUID(iid, this.iid) {
+		callbackAddRef(this)
+		*punk = (*ole.IUnknown)(unsafe.Pointer(this))
+		return ole.S_OK
+	}
+	return ole.E_NOINTERFACE
+}
+
+func callbackAddRef(this *callback) uintptr {
+	liveCallbacksMu.Lock()
+	defer liveCallbacksMu.Unlock()
+	this.ref++
+	return uintptr(this.ref)
+}
+
+func callbackRelease(this *callback) uintptr {
+	liveCallbacksMu.Lock()
+	defer liveCallbacksMu.Unlock()
+	this.ref--
+	if this.ref == 0 {
+		delete(liveCallbacks, this)
+	}
+	return uintptr(this.ref)
+}
+
+func callbackInvoke(this *callback, job *ole.IDispatch, args *ole.IDispatch) uintptr {
+	if this.invoke != nil {
+		this.invoke(job, args)
+	}
+	return ole.S_OK
+}
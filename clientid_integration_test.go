@@ -0,0 +1,88 @@
+//go:build windows && integration
+// +build windows,integration
+
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import "testing"
+
+const integrationClientApplicationID = "windowsupdate-integration-test"
+
+// TestClientApplicationIDRoundTrip installs an applicable update and must be run elevated on a
+// disposable Windows machine: go test -tags integration -run TestClientApplicationIDRoundTrip
+func TestClientApplicationIDRoundTrip(t *testing.T) {
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer Uninitialize()
+
+	session, err := NewUpdateSessionWithClientID(integrationClientApplicationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	searcher, err := session.CreateUpdateSearcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := searcher.Search("IsInstalled=0 and IsHidden=0 and Type='Software'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates, err := result.Updates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) == 0 {
+		t.Skip("no applicable updates to install")
+	}
+	updates = updates[:1]
+	if err := updates[0].AcceptEula(); err != nil {
+		t.Fatal(err)
+	}
+
+	downloader, err := session.CreateUpdateDownloader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := downloader.Download(updates); err != nil {
+		t.Fatal(err)
+	}
+	installer, err := session.CreateUpdateInstaller()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := installer.Install(updates); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := searcher.QueryHistoryAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := updates[0].Identity
+	for _, entry := range entries {
+		if entry.UpdateIdentity == nil || entry.UpdateIdentity.UpdateID != identity.UpdateID || entry.UpdateIdentity.RevisionNumber != identity.RevisionNumber {
+			continue
+		}
+		// The history is ordered newest first, so the first match is the installation above.
+		if entry.ClientApplicationID != integrationClientApplicationID {
+			t.Errorf("history entry %q has ClientApplicationID %q, want %q", entry.Title, entry.ClientApplicationID, integrationClientApplicationID)
+		}
+		return
+	}
+	t.Fatalf("no history entry for update %s revision %d", identity.UpdateID, identity.RevisionNumber)
+}
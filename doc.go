@@ -0,0 +1,34 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package windowsupdate is a binding for the Windows Update Agent (WUA) API.
+
+WUA objects are COM objects that belong to the apartment of the thread that created them,
+and must only be used from that thread. COM has to be initialized on the calling thread
+before calling NewUpdateSession, which is what Initialize does:
+
+	if err := windowsupdate.Initialize(); err != nil {
+		return err
+	}
+	defer windowsupdate.Uninitialize()
+
+	session, err := windowsupdate.NewUpdateSession()
+
+Initialize and NewUpdateSession lock the goroutine to its OS thread with runtime.LockOSThread
+until Uninitialize and Close are called, so the session and every searcher, downloader,
+installer and update retrieved from it stay on that thread. Callers that initialize COM
+themselves with ole.CoInitializeEx must lock the goroutine to its thread first.
+Independent goroutines can each create and use their own session concurrently.
+*/
+package windowsupdate
@@ -0,0 +1,167 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"context"
+)
+
+// DownloadUpdates searches for the updates matching criteria and downloads those that are not
+// downloaded yet. It returns the result of the download and the updates that were downloaded.
+// If all the updates found are already downloaded, the result is nil and no download is started.
+func (iUpdateSession *IUpdateSession) DownloadUpdates(criteria string) (*IDownloadResult, []*IUpdate, error) {
+	searcher, err := iUpdateSession.CreateUpdateSearcher()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	searchResult, err := searcher.Search(criteria)
+	if err != nil {
+		return nil, nil, err
+	}
+	searchUpdates, err := searchResult.Updates()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updates := make([]*IUpdate, 0, len(searchUpdates))
+	for _, update := range searchUpdates {
+		if !update.IsDownloaded {
+			updates = append(updates, update)
+		}
+	}
+	if len(updates) == 0 {
+		return nil, updates, nil
+	}
+
+	downloader, err := iUpdateSession.CreateUpdateDownloader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	downloadResult, err := downloader.Download(updates)
+	if err != nil {
+		return nil, nil, err
+	}
+	return downloadResult, updates, nil
+}
+
+// DownloadProgress is a snapshot of the progress of a download started by IUpdateDownloader.DownloadWithProgress.
+type DownloadProgress struct {
+	CurrentUpdateBytesDownloaded int64
+	CurrentUpdateBytesToDownload int64
+	CurrentUpdateDownloadPhase   int32
+	CurrentUpdateIndex           int32
+	CurrentUpdatePercentComplete int32
+	PercentComplete              int32
+	TotalBytesDownloaded         int64
+	TotalBytesToDownload         int64
+}
+
+// DownloadWithProgress downloads the updates of the downloader, see SetUpdates, in the background and reports
+// its progress on the first channel. The second channel receives the outcome once the download is over: nil if
+// it succeeded, the error of the result otherwise, e.g. a *WUAError. The progress channel is closed before the
+// outcome is sent. Progress reports are dropped while the previous one has not been received. Cancelling ctx
+// aborts the download.
+//
+// WUA objects can only be used on the thread that created them, and their callbacks are only delivered while that
+// thread waits for the job. So that the channels can be read from any goroutine, including the downloader's, the
+// download runs on a thread of its own, with a new session that takes over the ClientApplicationID of the downloader
+// and the WebProxy of its session, except for the password. In that session, the updates are searched for again by
+// identity, with the Online, ServerSelection and ServiceID of the searcher that found them. This search is not free:
+// online, it contacts the server again, and the updates of a service that was removed since, such as the
+// temporary service of ScanOffline, are not found and make the download fail with ErrUpdateNotFound.
+// IsForced and Priority are taken over from the downloader.
+func (iUpdateDownloader *IUpdateDownloader) DownloadWithProgress(ctx context.Context) (<-chan DownloadProgress, <-chan error) {
+	progressChan := make(chan DownloadProgress, 1)
+	errChan := make(chan error, 1)
+
+	priority, err := iUpdateDownloader.Priority()
+	if err == nil {
+		err = checkWritable(iUpdateDownloader.session)
+	}
+	if err == nil && len(iUpdateDownloader.Updates) == 0 {
+		err = ErrNoUpdatesSelected
+	}
+	if err != nil {
+		close(progressChan)
+		errChan <- err
+		close(errChan)
+		return progressChan, errChan
+	}
+	updates := iUpdateDownloader.Updates
+	isForced := iUpdateDownloader.IsForced
+	settings := iUpdateDownloader.session.settings(iUpdateDownloader.ClientApplicationID)
+
+	go func() {
+		err := func() error {
+			defer close(progressChan)
+
+			if err := Initialize(); err != nil {
+				return err
+			}
+			defer Uninitialize()
+
+			session, err := settings.newSession()
+			if err != nil {
+				return err
+			}
+			defer session.Close()
+
+			sessionUpdates, err := session.findUpdates(updates)
+			if err != nil {
+				return err
+			}
+
+			downloader, err := session.CreateUpdateDownloader()
+			if err != nil {
+				return err
+			}
+			if err := downloader.SetIsForced(isForced); err != nil {
+				return err
+			}
+			if err := downloader.SetPriority(priority); err != nil {
+				return err
+			}
+
+			job, err := downloader.BeginDownload(ctx, sessionUpdates, func(progress *IDownloadProgress) {
+				select {
+				case progressChan <- DownloadProgress{
+					CurrentUpdateBytesDownloaded: progress.CurrentUpdateBytesDownloaded,
+					CurrentUpdateBytesToDownload: progress.CurrentUpdateBytesToDownload,
+					CurrentUpdateDownloadPhase:   progress.CurrentUpdateDownloadPhase,
+					CurrentUpdateIndex:           progress.CurrentUpdateIndex,
+					CurrentUpdatePercentComplete: progress.CurrentUpdatePercentComplete,
+					PercentComplete:              progress.PercentComplete,
+					TotalBytesDownloaded:         progress.TotalBytesDownloaded,
+					TotalBytesToDownload:         progress.TotalBytesToDownload,
+				}:
+				default:
+				}
+			})
+			if err != nil {
+				return err
+			}
+			result, err := downloader.EndDownload(job)
+			if err != nil {
+				return err
+			}
+			return result.Err()
+		}()
+		errChan <- err
+		close(errChan)
+	}()
+
+	return progressChan, errChan
+}
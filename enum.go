@@ -13,13 +13,174 @@ limitations under the License.
 
 package windowsupdate
 
+import (
+	"fmt"
+)
+
 // OperationResultCode defines the possible results of a download, install, uninstall, or verification operation on an update.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-operationresultcode
+type OperationResultCode int32
+
 const (
-	OperationResultCodeOrcNotStarted int32 = iota
+	OperationResultCodeOrcNotStarted OperationResultCode = iota
 	OperationResultCodeOrcInProgress
 	OperationResultCodeOrcSucceeded
 	OperationResultCodeOrcSucceededWithErrors
 	OperationResultCodeOrcFailed
 	OperationResultCodeOrcAborted
 )
+
+func (operationResultCode OperationResultCode) String() string {
+	switch operationResultCode {
+	case OperationResultCodeOrcNotStarted:
+		return "NotStarted"
+	case OperationResultCodeOrcInProgress:
+		return "InProgress"
+	case OperationResultCodeOrcSucceeded:
+		return "Succeeded"
+	case OperationResultCodeOrcSucceededWithErrors:
+		return "SucceededWithErrors"
+	case OperationResultCodeOrcFailed:
+		return "Failed"
+	case OperationResultCodeOrcAborted:
+		return "Aborted"
+	}
+	return fmt.Sprintf("OperationResultCode(%d)", int32(operationResultCode))
+}
+
+// ServerSelection defines values that indicate the type of server to search against.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-serverselection
+type ServerSelection int32
+
+const (
+	ServerSelectionSsDefault ServerSelection = iota
+	ServerSelectionSsManagedServer
+	ServerSelectionSsWindowsUpdate
+	ServerSelectionSsOthers
+)
+
+// AddServiceFlag defines the options for registering a service with the Windows Update Agent.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-addserviceflag
+type AddServiceFlag int32
+
+const (
+	AddServiceFlagAsfAllowPendingRegistration AddServiceFlag = 0x1
+	AddServiceFlagAsfAllowOnlineRegistration  AddServiceFlag = 0x2
+	AddServiceFlagAsfRegisterServiceWithAU    AddServiceFlag = 0x4
+)
+
+// UpdateServiceRegistrationState defines the states of the registration of a service with the Windows Update Agent.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-updateserviceregistrationstate
+type UpdateServiceRegistrationState int32
+
+const (
+	UpdateServiceRegistrationStateUsrsNotRegistered UpdateServiceRegistrationState = iota + 1
+	UpdateServiceRegistrationStateUsrsRegistrationPending
+	UpdateServiceRegistrationStateUsrsRegistered
+)
+
+// NotificationLevel defines the ways in which Automatic Updates notifies users of new updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-automaticupdatesnotificationlevel
+type NotificationLevel int32
+
+const (
+	NotificationLevelAunlNotConfigured NotificationLevel = iota
+	NotificationLevelAunlDisabled
+	NotificationLevelAunlNotifyBeforeDownload
+	NotificationLevelAunlNotifyBeforeInstallation
+	NotificationLevelAunlScheduledInstallation
+)
+
+// InstallationImpact defines the possible levels of impact that can be caused by installing or uninstalling an update.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-installationimpact
+type InstallationImpact int32
+
+const (
+	InstallationImpactIiNormal InstallationImpact = iota
+	InstallationImpactIiMinor
+	InstallationImpactIiRequiresExclusiveHandling
+)
+
+// InstallationRebootBehavior defines the restart behaviors of an update when it is installed or uninstalled.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-installationrebootbehavior
+type InstallationRebootBehavior int32
+
+const (
+	InstallationRebootBehaviorIrbNeverReboots InstallationRebootBehavior = iota
+	InstallationRebootBehaviorIrbAlwaysRequiresReboot
+	InstallationRebootBehaviorIrbCanRequestReboot
+)
+
+// DownloadPriority defines the possible priorities of a download.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-downloadpriority
+type DownloadPriority int32
+
+const (
+	DownloadPriorityDpLow DownloadPriority = iota + 1
+	DownloadPriorityDpNormal
+	DownloadPriorityDpHigh
+	DownloadPriorityDpExtraHigh
+)
+
+// UpdateExceptionContext defines the possible contexts of an IUpdateException.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-updateexceptioncontext
+type UpdateExceptionContext int32
+
+const (
+	UpdateExceptionContextUecGeneral UpdateExceptionContext = iota + 1
+	UpdateExceptionContextUecWindowsDriver
+	UpdateExceptionContextUecWindowsInstaller
+	UpdateExceptionContextUecSearchIncomplete
+)
+
+// UpdateType defines the types of updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-updatetype
+type UpdateType int32
+
+const (
+	UpdateTypeUtSoftware UpdateType = iota + 1
+	UpdateTypeUtDriver
+)
+
+func (updateType UpdateType) String() string {
+	switch updateType {
+	case UpdateTypeUtSoftware:
+		return "Software"
+	case UpdateTypeUtDriver:
+		return "Driver"
+	}
+	return fmt.Sprintf("UpdateType(%d)", int32(updateType))
+}
+
+// DeploymentAction defines the action for which an update is explicitly deployed.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-deploymentaction
+type DeploymentAction int32
+
+const (
+	DeploymentActionDaNone DeploymentAction = iota
+	DeploymentActionDaInstallation
+	DeploymentActionDaUninstallation
+	DeploymentActionDaDetection
+	DeploymentActionDaOptionalInstallation
+)
+
+// UpdateServiceOption defines the options of a scan package service added with IUpdateServiceManager.AddScanPackageService.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-updateserviceoption
+type UpdateServiceOption int32
+
+const (
+	UpdateServiceOptionUsoNonVolatileService UpdateServiceOption = 0x1
+)
+
+// SearchScope defines the scope of the updates that are searched for, per-machine updates or updates for users.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-searchscope
+type SearchScope int32
+
+const (
+	SearchScopeDefault SearchScope = iota
+	SearchScopeMachineOnly
+	SearchScopeCurrentUserOnly
+	SearchScopeMachineAndCurrentUser
+	SearchScopeMachineAndAllUsers
+	SearchScopeAllUsers
+)
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"errors"
+)
+
+// ErrUpdateNotUninstallable is returned when uninstalling an update whose IsUninstallable is false.
+var ErrUpdateNotUninstallable = errors.New("windowsupdate: the update cannot be uninstalled")
+
+// ErrReadOnlySession is returned when an operation that modifies an update or a setting is performed on objects of a read-only IUpdateSession.
+var ErrReadOnlySession = errors.New("windowsupdate: the update session is read-only")
+
+// ErrElevationRequired is returned when an operation requires the caller to run with administrator privileges.
+var ErrElevationRequired = errors.New("windowsupdate: the operation requires elevation")
+
+// ErrRebootRequired is returned when the computer must be restarted before updates can be installed.
+var ErrRebootRequired = errors.New("windowsupdate: a reboot is required before installing updates")
+
+// ErrEulaNotAccepted is reported by IUpdateInstaller.Validate for updates whose license terms are not accepted.
+var ErrEulaNotAccepted = errors.New("windowsupdate: the license terms of the update are not accepted")
+
+// ErrUpdateNotDownloaded is reported by IUpdateInstaller.Validate for updates whose content is not downloaded.
+var ErrUpdateNotDownloaded = errors.New("windowsupdate: the update is not downloaded")
+
+// ErrUpdateNotFound is returned when no update matches the requested identity.
+var ErrUpdateNotFound = errors.New("windowsupdate: the update was not found")
+
+// ErrSessionTimeout is returned by NewUpdateSessionTimeout when the Windows Update service does not respond in time.
+var ErrSessionTimeout = errors.New("windowsupdate: timed out creating the update session")
+
+// ErrNotDriverUpdate is returned by IUpdate.DriverUpdate for updates that are not driver updates.
+var ErrNotDriverUpdate = errors.New("windowsupdate: the update is not a driver update")
+
+// ErrNoUpdatesSelected is returned when downloading, installing or uninstalling an empty list of updates.
+var ErrNoUpdatesSelected = errors.New("windowsupdate: no updates are selected")
+
+// ErrSessionRunnerClosed is returned by SessionRunner.Do once the runner is closed.
+var ErrSessionRunnerClosed = errors.New("windowsupdate: the session runner is closed")
+
+// ErrNotDefaultAUService is returned by IUpdateServiceManager.SetDefaultAUService when the service is registered
+// but Automatic Updates does not use it, e.g. because its registration is still pending.
+var ErrNotDefaultAUService = errors.New("windowsupdate: the service is not the default Automatic Updates service")
+
+const (
+	hresultEAccessDenied  = 0x80070005
+	hresultENoInterface   = 0x80004002
+	hresultDispEException = 0x80020009
+)
@@ -9,6 +9,9 @@ import (
 	"github.com/scjalliance/comshim"
 )
 
+// clientApplicationID is recorded in the update history for the installations of this example.
+const clientApplicationID = "windowsupdate-example"
+
 func main() {
 	comshim.Add(1)
 	defer comshim.Done()
@@ -19,7 +22,7 @@ func main() {
 
 	var err error
 
-	session, err := windowsupdate.NewUpdateSession()
+	session, err := windowsupdate.NewUpdateSessionWithClientID(clientApplicationID)
 	if err != nil {
 		panic(err)
 	}
@@ -39,6 +42,11 @@ func main() {
 	b, _ := json.Marshal(result)
 	fmt.Println(string(b))
 
+	updates, err := result.Updates()
+	if err != nil {
+		panic(err)
+	}
+
 	// Download Updates
 	fmt.Println("Step 2: Download Updates")
 	downloader, err := session.CreateUpdateDownloader()
@@ -46,7 +54,7 @@ func main() {
 		panic(err)
 	}
 
-	downloadResult, err := downloader.Download(result.Updates)
+	downloadResult, err := downloader.Download(updates)
 	if err != nil {
 		panic(err)
 	}
@@ -61,11 +69,45 @@ func main() {
 		panic(err)
 	}
 
-	installationResult, err := installer.Install(result.Updates)
+	installationResult, err := installer.Install(updates)
 	if err != nil {
 		panic(err)
 	}
 
 	d, _ := json.Marshal(installationResult)
 	fmt.Println(string(d))
+
+	// Check the Update History
+	fmt.Println("Step 4: Check the Update History")
+	entries, err := searcher.QueryHistoryAll()
+	if err != nil {
+		panic(err)
+	}
+
+	// The history is ordered newest first and also holds operations from other clients, so only the
+	// latest entry of each installed update is compared.
+	pending := make(map[string]bool, len(updates))
+	for _, update := range updates {
+		if update.Identity != nil {
+			pending[historyKey(update.Identity)] = true
+		}
+	}
+	for _, entry := range entries {
+		key := historyKey(entry.UpdateIdentity)
+		if !pending[key] {
+			continue
+		}
+		delete(pending, key)
+		if entry.ClientApplicationID != clientApplicationID {
+			panic(fmt.Sprintf("history entry %q has ClientApplicationID %q, want %q", entry.Title, entry.ClientApplicationID, clientApplicationID))
+		}
+	}
+	fmt.Printf("%d history entries have ClientApplicationID %q\n", len(updates)-len(pending), clientApplicationID)
+}
+
+func historyKey(identity *windowsupdate.IUpdateIdentity) string {
+	if identity == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s.%d", identity.UpdateID, identity.RevisionNumber)
 }
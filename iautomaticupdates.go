@@ -0,0 +1,77 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IAutomaticUpdates contains the functionality of Automatic Updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iautomaticupdates
+type IAutomaticUpdates struct {
+	disp *ole.IDispatch
+}
+
+// NewAutomaticUpdates creates a new IAutomaticUpdates interface.
+func NewAutomaticUpdates() (*IAutomaticUpdates, error) {
+	unknown, err := oleutil.CreateObject("Microsoft.Update.AutoUpdate")
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	disp, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &IAutomaticUpdates{
+		disp: disp,
+	}, nil
+}
+
+// ServiceEnabled gets a Boolean value that indicates whether all the components that Automatic Updates requires are available.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdates-get_serviceenabled
+func (iAutomaticUpdates *IAutomaticUpdates) ServiceEnabled() (bool, error) {
+	return toBoolErr(oleutil.GetProperty(iAutomaticUpdates.disp, "ServiceEnabled"))
+}
+
+// EnableService enables all the components that Automatic Updates requires.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdates-enableservice
+func (iAutomaticUpdates *IAutomaticUpdates) EnableService() error {
+	_, err := oleutil.CallMethod(iAutomaticUpdates.disp, "EnableService")
+	return wrapError(err)
+}
+
+// Pause pauses automatic updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdates-pause
+func (iAutomaticUpdates *IAutomaticUpdates) Pause() error {
+	_, err := oleutil.CallMethod(iAutomaticUpdates.disp, "Pause")
+	return wrapError(err)
+}
+
+// Resume restarts automatic updating if automatic updating is paused.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdates-resume
+func (iAutomaticUpdates *IAutomaticUpdates) Resume() error {
+	_, err := oleutil.CallMethod(iAutomaticUpdates.disp, "Resume")
+	return wrapError(err)
+}
+
+// Settings gets the configuration settings for Automatic Updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdates-get_settings
+func (iAutomaticUpdates *IAutomaticUpdates) Settings() (*IAutomaticUpdatesSettings, error) {
+	settingsDisp, err := toIDispatchErr(oleutil.GetProperty(iAutomaticUpdates.disp, "Settings"))
+	if err != nil {
+		return nil, err
+	}
+	return toIAutomaticUpdatesSettings(settingsDisp)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IAutomaticUpdatesSettings contains the read/write properties that are available to Automatic Updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iautomaticupdatessettings
+type IAutomaticUpdatesSettings struct {
+	disp                      *ole.IDispatch
+	NotificationLevel         NotificationLevel
+	ReadOnly                  bool
+	Required                  bool
+	ScheduledInstallationDay  int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-automaticupdatesscheduledinstallationday
+	ScheduledInstallationTime int32
+}
+
+func toIAutomaticUpdatesSettings(automaticUpdatesSettingsDisp *ole.IDispatch) (*IAutomaticUpdatesSettings, error) {
+	iAutomaticUpdatesSettings := &IAutomaticUpdatesSettings{
+		disp: automaticUpdatesSettingsDisp,
+	}
+
+	notificationLevel, err := toInt32Err(oleutil.GetProperty(automaticUpdatesSettingsDisp, "NotificationLevel"))
+	if err != nil {
+		return nil, err
+	}
+	iAutomaticUpdatesSettings.NotificationLevel = NotificationLevel(notificationLevel)
+
+	if iAutomaticUpdatesSettings.ReadOnly, err = toBoolErr(oleutil.GetProperty(automaticUpdatesSettingsDisp, "ReadOnly")); err != nil {
+		return nil, err
+	}
+
+	if iAutomaticUpdatesSettings.Required, err = toBoolErr(oleutil.GetProperty(automaticUpdatesSettingsDisp, "Required")); err != nil {
+		return nil, err
+	}
+
+	if iAutomaticUpdatesSettings.ScheduledInstallationDay, err = toInt32Err(oleutil.GetProperty(automaticUpdatesSettingsDisp, "ScheduledInstallationDay")); err != nil {
+		return nil, err
+	}
+
+	if iAutomaticUpdatesSettings.ScheduledInstallationTime, err = toInt32Err(oleutil.GetProperty(automaticUpdatesSettingsDisp, "ScheduledInstallationTime")); err != nil {
+		return nil, err
+	}
+
+	return iAutomaticUpdatesSettings, nil
+}
+
+// SetNotificationLevel sets how Automatic Updates notifies users of new updates. The change is persisted by Save.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdatessettings-put_notificationlevel
+func (iAutomaticUpdatesSettings *IAutomaticUpdatesSettings) SetNotificationLevel(notificationLevel NotificationLevel) error {
+	if _, err := oleutil.PutProperty(iAutomaticUpdatesSettings.disp, "NotificationLevel", int32(notificationLevel)); err != nil {
+		return wrapError(err)
+	}
+	iAutomaticUpdatesSettings.NotificationLevel = notificationLevel
+	return nil
+}
+
+// SetScheduledInstallationDay sets the days of the week on which Automatic Updates installs or uninstalls updates. The change is persisted by Save.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdatessettings-put_scheduledinstallationday
+func (iAutomaticUpdatesSettings *IAutomaticUpdatesSettings) SetScheduledInstallationDay(scheduledInstallationDay int32) error {
+	if _, err := oleutil.PutProperty(iAutomaticUpdatesSettings.disp, "ScheduledInstallationDay", scheduledInstallationDay); err != nil {
+		return wrapError(err)
+	}
+	iAutomaticUpdatesSettings.ScheduledInstallationDay = scheduledInstallationDay
+	return nil
+}
+
+// SetScheduledInstallationTime sets the hour, 0 to 23, at which Automatic Updates installs or uninstalls updates. The change is persisted by Save.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdatessettings-put_scheduledinstallationtime
+func (iAutomaticUpdatesSettings *IAutomaticUpdatesSettings) SetScheduledInstallationTime(scheduledInstallationTime int32) error {
+	if _, err := oleutil.PutProperty(iAutomaticUpdatesSettings.disp, "ScheduledInstallationTime", scheduledInstallationTime); err != nil {
+		return wrapError(err)
+	}
+	iAutomaticUpdatesSettings.ScheduledInstallationTime = scheduledInstallationTime
+	return nil
+}
+
+// Refresh retrieves the latest Automatic Updates settings.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdatessettings-refresh
+func (iAutomaticUpdatesSettings *IAutomaticUpdatesSettings) Refresh() error {
+	if _, err := oleutil.CallMethod(iAutomaticUpdatesSettings.disp, "Refresh"); err != nil {
+		return wrapError(err)
+	}
+	refreshed, err := toIAutomaticUpdatesSettings(iAutomaticUpdatesSettings.disp)
+	if err != nil {
+		return err
+	}
+	*iAutomaticUpdatesSettings = *refreshed
+	return nil
+}
+
+// Save applies the current Automatic Updates settings. It requires administrator privileges and fails if ReadOnly is true.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iautomaticupdatessettings-save
+func (iAutomaticUpdatesSettings *IAutomaticUpdatesSettings) Save() error {
+	_, err := oleutil.CallMethod(iAutomaticUpdatesSettings.disp, "Save")
+	return wrapError(err)
+}
@@ -28,12 +28,18 @@ type ICategory struct {
 	Image       *IImageInformation
 	Name        string
 	Order       int32
-	Parent      *ICategory
+	Parent      *ICategory `json:"-"`
 	Type        string
-	Updates     []*IUpdate
 }
 
 func toICategories(categoriesDisp *ole.IDispatch) ([]*ICategory, error) {
+	return toICategoriesVisited(categoriesDisp, map[string]*ICategory{})
+}
+
+// toICategoriesVisited converts an ICategoryCollection. visited maps the CategoryID of every category
+// converted so far to its ICategory, so that each category of the graph is converted once even if it
+// is reachable through several parents or children, and cycles in the graph terminate.
+func toICategoriesVisited(categoriesDisp *ole.IDispatch, visited map[string]*ICategory) ([]*ICategory, error) {
 	count, err := toInt32Err(oleutil.GetProperty(categoriesDisp, "Count"))
 	if err != nil {
 		return nil, err
@@ -46,7 +52,7 @@ func toICategories(categoriesDisp *ole.IDispatch) ([]*ICategory, error) {
 			return nil, err
 		}
 
-		category, err := toICategory(categoryDisp)
+		category, err := toICategory(categoryDisp, visited)
 		if err != nil {
 			return nil, err
 		}
@@ -56,24 +62,35 @@ func toICategories(categoriesDisp *ole.IDispatch) ([]*ICategory, error) {
 	return categories, nil
 }
 
-func toICategory(categoryDisp *ole.IDispatch) (*ICategory, error) {
-	var err error
-	iCategory := &ICategory{
-		disp: categoryDisp,
+func toICategory(categoryDisp *ole.IDispatch, visited map[string]*ICategory) (*ICategory, error) {
+	categoryID, err := toStringErr(oleutil.GetProperty(categoryDisp, "CategoryID"))
+	if err != nil {
+		return nil, err
+	}
+	if iCategory, ok := visited[categoryID]; ok {
+		categoryDisp.Release()
+		return iCategory, nil
 	}
 
-	if iCategory.CategoryID, err = toStringErr(oleutil.GetProperty(categoryDisp, "CategoryID")); err != nil {
-		return nil, err
+	iCategory := &ICategory{
+		disp:       categoryDisp,
+		CategoryID: categoryID,
 	}
+	visited[categoryID] = iCategory
 
 	childrenDisp, err := toIDispatchErr(oleutil.GetProperty(categoryDisp, "Children"))
 	if err != nil {
 		return nil, err
 	}
 	if childrenDisp != nil {
-		if iCategory.Children, err = toICategories(childrenDisp); err != nil {
+		if iCategory.Children, err = toICategoriesVisited(childrenDisp, visited); err != nil {
 			return nil, err
 		}
+		for _, child := range iCategory.Children {
+			if child.Parent == nil {
+				child.Parent = iCategory
+			}
+		}
 	}
 
 	if iCategory.Description, err = toStringErr(oleutil.GetProperty(categoryDisp, "Description")); err != nil {
@@ -98,29 +115,33 @@ func toICategory(categoryDisp *ole.IDispatch) (*ICategory, error) {
 		return nil, err
 	}
 
-	// parentDisp, err := toIDispatchErr(oleutil.GetProperty(categoryDisp, "Parent"))
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// if parentDisp != nil {
-	// 	if iCategory.Parent, err = toICategory(parentDisp); err != nil {
-	// 		return nil, err
-	// 	}
-	// }
+	parentDisp, err := toIDispatchErr(oleutil.GetProperty(categoryDisp, "Parent"))
+	if err != nil {
+		return nil, err
+	}
+	if parentDisp != nil {
+		if iCategory.Parent, err = toICategory(parentDisp, visited); err != nil {
+			return nil, err
+		}
+	}
 
 	if iCategory.Type, err = toStringErr(oleutil.GetProperty(categoryDisp, "Type")); err != nil {
 		return nil, err
 	}
 
-	// updatesDisp, err := toIDispatchErr(oleutil.GetProperty(categoryDisp, "Updates"))
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// if updatesDisp != nil {
-	// 	if iCategory.Updates, err = toIUpdates(updatesDisp); err != nil {
-	// 		return nil, err
-	// 	}
-	// }
-
 	return iCategory, nil
 }
+
+// Updates gets the updates that belong to the category. They are retrieved on each call rather than
+// when the category is loaded, since every update refers back to its own categories.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-icategory-get_updates
+func (iCategory *ICategory) Updates() ([]*IUpdate, error) {
+	updatesDisp, err := toIDispatchErr(oleutil.GetProperty(iCategory.disp, "Updates"))
+	if err != nil {
+		return nil, err
+	}
+	if updatesDisp == nil {
+		return []*IUpdate{}, nil
+	}
+	return toIUpdates(updatesDisp)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"context"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IDownloadJob represents an asynchronous download operation started by IUpdateDownloader.BeginDownload.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-idownloadjob
+type IDownloadJob struct {
+	disp              *ole.IDispatch
+	ctx               context.Context
+	onProgressChanged *ole.IDispatch
+	onCompleted       *ole.IDispatch
+	updates           []*IUpdate
+}
+
+func toIDownloadJob(downloadJobDisp *ole.IDispatch) (*IDownloadJob, error) {
+	return &IDownloadJob{
+		disp: downloadJobDisp,
+	}, nil
+}
+
+// IsCompleted gets a Boolean value that indicates whether the call to IUpdateDownloader.BeginDownload is completely processed.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-idownloadjob-get_iscompleted
+func (iDownloadJob *IDownloadJob) IsCompleted() (bool, error) {
+	return toBoolErr(oleutil.GetProperty(iDownloadJob.disp, "IsCompleted"))
+}
+
+// GetProgress returns the current progress of the download.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-idownloadjob-getprogress
+func (iDownloadJob *IDownloadJob) GetProgress() (*IDownloadProgress, error) {
+	downloadProgressDisp, err := toIDispatchErr(oleutil.CallMethod(iDownloadJob.disp, "GetProgress"))
+	if err != nil {
+		return nil, err
+	}
+	return toIDownloadProgress(downloadProgressDisp)
+}
+
+// RequestAbort makes a request to cancel the asynchronous download.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-idownloadjob-requestabort
+func (iDownloadJob *IDownloadJob) RequestAbort() error {
+	_, err := oleutil.CallMethod(iDownloadJob.disp, "RequestAbort")
+	return wrapError(err)
+}
+
+// CleanUp waits for an asynchronous operation to complete and releases all the callbacks.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-idownloadjob-cleanup
+func (iDownloadJob *IDownloadJob) CleanUp() error {
+	_, err := oleutil.CallMethod(iDownloadJob.disp, "CleanUp")
+	iDownloadJob.releaseCallbacks()
+	return wrapError(err)
+}
+
+func (iDownloadJob *IDownloadJob) releaseCallbacks() {
+	releaseCallback(iDownloadJob.onProgressChanged)
+	releaseCallback(iDownloadJob.onCompleted)
+	iDownloadJob.onProgressChanged = nil
+	iDownloadJob.onCompleted = nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IDownloadProgress represents the progress of an asynchronous download operation.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-idownloadprogress
+type IDownloadProgress struct {
+	disp                         *ole.IDispatch
+	CurrentUpdateBytesDownloaded int64
+	CurrentUpdateBytesToDownload int64
+	CurrentUpdateDownloadPhase   int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-downloadphase
+	CurrentUpdateIndex           int32
+	CurrentUpdatePercentComplete int32
+	PercentComplete              int32
+	TotalBytesDownloaded         int64
+	TotalBytesToDownload         int64
+}
+
+func toIDownloadProgress(downloadProgressDisp *ole.IDispatch) (*IDownloadProgress, error) {
+	var err error
+	iDownloadProgress := &IDownloadProgress{
+		disp: downloadProgressDisp,
+	}
+
+	if iDownloadProgress.CurrentUpdateBytesDownloaded, err = toInt64Err(oleutil.GetProperty(downloadProgressDisp, "CurrentUpdateBytesDownloaded")); err != nil {
+		return nil, err
+	}
+
+	if iDownloadProgress.CurrentUpdateBytesToDownload, err = toInt64Err(oleutil.GetProperty(downloadProgressDisp, "CurrentUpdateBytesToDownload")); err != nil {
+		return nil, err
+	}
+
+	if iDownloadProgress.CurrentUpdateDownloadPhase, err = toInt32Err(oleutil.GetProperty(downloadProgressDisp, "CurrentUpdateDownloadPhase")); err != nil {
+		return nil, err
+	}
+
+	if iDownloadProgress.CurrentUpdateIndex, err = toInt32Err(oleutil.GetProperty(downloadProgressDisp, "CurrentUpdateIndex")); err != nil {
+		return nil, err
+	}
+
+	if iDownloadProgress.CurrentUpdatePercentComplete, err = toInt32Err(oleutil.GetProperty(downloadProgressDisp, "CurrentUpdatePercentComplete")); err != nil {
+		return nil, err
+	}
+
+	if iDownloadProgress.PercentComplete, err = toInt32Err(oleutil.GetProperty(downloadProgressDisp, "PercentComplete")); err != nil {
+		return nil, err
+	}
+
+	if iDownloadProgress.TotalBytesDownloaded, err = toInt64Err(oleutil.GetProperty(downloadProgressDisp, "TotalBytesDownloaded")); err != nil {
+		return nil, err
+	}
+
+	if iDownloadProgress.TotalBytesToDownload, err = toInt64Err(oleutil.GetProperty(downloadProgressDisp, "TotalBytesToDownload")); err != nil {
+		return nil, err
+	}
+
+	return iDownloadProgress, nil
+}
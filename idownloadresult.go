@@ -21,22 +21,19 @@ import (
 // IDownloadResult represents the result of a download operation.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-idownloadresult
 type IDownloadResult struct {
-	disp       *ole.IDispatch
-	HResult    int32
-	ResultCode int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-operationresultcode
+	disp *ole.IDispatch
+	OperationResult
+	updates []*IUpdate // the updates that were downloaded
 }
 
-func toIDownloadResult(downloadResultDisp *ole.IDispatch) (*IDownloadResult, error) {
+func toIDownloadResult(downloadResultDisp *ole.IDispatch, updates []*IUpdate) (*IDownloadResult, error) {
 	var err error
 	iDownloadResult := &IDownloadResult{
-		disp: downloadResultDisp,
+		disp:    downloadResultDisp,
+		updates: updates,
 	}
 
-	if iDownloadResult.HResult, err = toInt32Err(oleutil.GetProperty(downloadResultDisp, "HResult")); err != nil {
-		return nil, err
-	}
-
-	if iDownloadResult.ResultCode, err = toInt32Err(oleutil.GetProperty(downloadResultDisp, "ResultCode")); err != nil {
+	if iDownloadResult.OperationResult, err = toOperationResult(downloadResultDisp); err != nil {
 		return nil, err
 	}
 
@@ -44,8 +41,15 @@ func toIDownloadResult(downloadResultDisp *ole.IDispatch) (*IDownloadResult, err
 }
 
 // GetUpdateResult returns an IUpdateDownloadResult interface that contains the download information for a specified update.
+// updateIndex is the index of the update in the updates passed to IUpdateDownloader.Download or BeginDownload.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-idownloadresult-getupdateresult
 func (iDownloadResult *IDownloadResult) GetUpdateResult(updateIndex int32) (*IUpdateDownloadResult, error) {
-	// TODO
-	return nil, nil
+	if err := checkUpdateIndex(updateIndex, len(iDownloadResult.updates)); err != nil {
+		return nil, err
+	}
+	updateDownloadResultDisp, err := toIDispatchErr(oleutil.CallMethod(iDownloadResult.disp, "GetUpdateResult", updateIndex))
+	if err != nil {
+		return nil, err
+	}
+	return toIUpdateDownloadResult(updateDownloadResultDisp)
 }
@@ -15,19 +15,45 @@ package windowsupdate
 
 import (
 	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
 )
 
 // IInstallationBehavior represents the installation and uninstallation options of an update.
+// WUA uses the same interface for IUpdate.InstallationBehavior and IUpdate.UninstallationBehavior.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iinstallationbehavior
 type IInstallationBehavior struct {
 	disp                        *ole.IDispatch
 	CanRequestUserInput         bool
-	Impact                      int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-installationimpact
-	RebootBehavior              int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-installationrebootbehavior
+	Impact                      InstallationImpact
+	RebootBehavior              InstallationRebootBehavior
 	RequiresNetworkConnectivity bool
 }
 
 func toIInstallationBehavior(installationBehaviorDisp *ole.IDispatch) (*IInstallationBehavior, error) {
-	// TODO
-	return nil, nil
+	var err error
+	iInstallationBehavior := &IInstallationBehavior{
+		disp: installationBehaviorDisp,
+	}
+
+	if iInstallationBehavior.CanRequestUserInput, err = toBoolErr(oleutil.GetProperty(installationBehaviorDisp, "CanRequestUserInput")); err != nil {
+		return nil, err
+	}
+
+	impact, err := toInt32Err(oleutil.GetProperty(installationBehaviorDisp, "Impact"))
+	if err != nil {
+		return nil, err
+	}
+	iInstallationBehavior.Impact = InstallationImpact(impact)
+
+	rebootBehavior, err := toInt32Err(oleutil.GetProperty(installationBehaviorDisp, "RebootBehavior"))
+	if err != nil {
+		return nil, err
+	}
+	iInstallationBehavior.RebootBehavior = InstallationRebootBehavior(rebootBehavior)
+
+	if iInstallationBehavior.RequiresNetworkConnectivity, err = toBoolErr(oleutil.GetProperty(installationBehaviorDisp, "RequiresNetworkConnectivity")); err != nil {
+		return nil, err
+	}
+
+	return iInstallationBehavior, nil
 }
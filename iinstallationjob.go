@@ -0,0 +1,75 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"context"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IInstallationJob represents an asynchronous installation or uninstallation started by IUpdateInstaller.BeginInstall.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iinstallationjob
+type IInstallationJob struct {
+	disp              *ole.IDispatch
+	ctx               context.Context
+	onProgressChanged *ole.IDispatch
+	onCompleted       *ole.IDispatch
+	updates           []*IUpdate
+}
+
+func toIInstallationJob(installationJobDisp *ole.IDispatch) (*IInstallationJob, error) {
+	return &IInstallationJob{
+		disp: installationJobDisp,
+	}, nil
+}
+
+// IsCompleted gets a Boolean value that indicates whether the call to IUpdateInstaller.BeginInstall is completely processed.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iinstallationjob-get_iscompleted
+func (iInstallationJob *IInstallationJob) IsCompleted() (bool, error) {
+	return toBoolErr(oleutil.GetProperty(iInstallationJob.disp, "IsCompleted"))
+}
+
+// GetProgress returns the current progress of the installation.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iinstallationjob-getprogress
+func (iInstallationJob *IInstallationJob) GetProgress() (*IInstallationProgress, error) {
+	installationProgressDisp, err := toIDispatchErr(oleutil.CallMethod(iInstallationJob.disp, "GetProgress"))
+	if err != nil {
+		return nil, err
+	}
+	return toIInstallationProgress(installationProgressDisp)
+}
+
+// RequestAbort makes a request to cancel the asynchronous installation.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iinstallationjob-requestabort
+func (iInstallationJob *IInstallationJob) RequestAbort() error {
+	_, err := oleutil.CallMethod(iInstallationJob.disp, "RequestAbort")
+	return wrapError(err)
+}
+
+// CleanUp waits for an asynchronous operation to complete and releases all the callbacks.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iinstallationjob-cleanup
+func (iInstallationJob *IInstallationJob) CleanUp() error {
+	_, err := oleutil.CallMethod(iInstallationJob.disp, "CleanUp")
+	iInstallationJob.releaseCallbacks()
+	return wrapError(err)
+}
+
+func (iInstallationJob *IInstallationJob) releaseCallbacks() {
+	releaseCallback(iInstallationJob.onProgressChanged)
+	releaseCallback(iInstallationJob.onCompleted)
+	iInstallationJob.onProgressChanged = nil
+	iInstallationJob.onCompleted = nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IInstallationProgress represents the progress of an asynchronous installation or uninstallation.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iinstallationprogress
+type IInstallationProgress struct {
+	disp                         *ole.IDispatch
+	CurrentUpdateIndex           int32
+	CurrentUpdatePercentComplete int32
+	PercentComplete              int32
+}
+
+func toIInstallationProgress(installationProgressDisp *ole.IDispatch) (*IInstallationProgress, error) {
+	var err error
+	iInstallationProgress := &IInstallationProgress{
+		disp: installationProgressDisp,
+	}
+
+	if iInstallationProgress.CurrentUpdateIndex, err = toInt32Err(oleutil.GetProperty(installationProgressDisp, "CurrentUpdateIndex")); err != nil {
+		return nil, err
+	}
+
+	if iInstallationProgress.CurrentUpdatePercentComplete, err = toInt32Err(oleutil.GetProperty(installationProgressDisp, "CurrentUpdatePercentComplete")); err != nil {
+		return nil, err
+	}
+
+	if iInstallationProgress.PercentComplete, err = toInt32Err(oleutil.GetProperty(installationProgressDisp, "PercentComplete")); err != nil {
+		return nil, err
+	}
+
+	return iInstallationProgress, nil
+}
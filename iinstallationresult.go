@@ -21,19 +21,20 @@ import (
 // IInstallationResult represents the result of an installation or uninstallation.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iinstallationresult
 type IInstallationResult struct {
-	disp           *ole.IDispatch
-	HResult        int32
+	disp *ole.IDispatch
+	OperationResult
 	RebootRequired bool
-	ResultCode     int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-operationresultcode
+	updates        []*IUpdate // the updates that were installed or uninstalled
 }
 
-func toIInstallationResult(installationResultDisp *ole.IDispatch) (*IInstallationResult, error) {
+func toIInstallationResult(installationResultDisp *ole.IDispatch, updates []*IUpdate) (*IInstallationResult, error) {
 	var err error
 	iInstallationResult := &IInstallationResult{
-		disp: installationResultDisp,
+		disp:    installationResultDisp,
+		updates: updates,
 	}
 
-	if iInstallationResult.HResult, err = toInt32Err(oleutil.GetProperty(installationResultDisp, "HResult")); err != nil {
+	if iInstallationResult.OperationResult, err = toOperationResult(installationResultDisp); err != nil {
 		return nil, err
 	}
 
@@ -41,9 +42,19 @@ func toIInstallationResult(installationResultDisp *ole.IDispatch) (*IInstallatio
 		return nil, err
 	}
 
-	if iInstallationResult.ResultCode, err = toInt32Err(oleutil.GetProperty(installationResultDisp, "ResultCode")); err != nil {
+	return iInstallationResult, nil
+}
+
+// GetUpdateResult returns an IUpdateInstallationResult interface that contains the installation results for a specified update.
+// updateIndex is the index of the update in the updates passed to IUpdateInstaller.Install, Uninstall or BeginInstall.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iinstallationresult-getupdateresult
+func (iInstallationResult *IInstallationResult) GetUpdateResult(updateIndex int32) (*IUpdateInstallationResult, error) {
+	if err := checkUpdateIndex(updateIndex, len(iInstallationResult.updates)); err != nil {
 		return nil, err
 	}
-
-	return iInstallationResult, nil
+	updateInstallationResultDisp, err := toIDispatchErr(oleutil.CallMethod(iInstallationResult.disp, "GetUpdateResult", updateIndex))
+	if err != nil {
+		return nil, err
+	}
+	return toIUpdateInstallationResult(updateInstallationResultDisp)
 }
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"runtime"
+
+	"github.com/go-ole/go-ole"
+)
+
+// sFalse is returned by CoInitializeEx when COM is already initialized on the thread.
+const sFalse = 0x00000001
+
+// Initialize locks the calling goroutine to its OS thread and initializes COM on that thread as a
+// single-threaded apartment, so that sessions can be created on it with NewUpdateSession.
+// It is safe to call Initialize on a thread where COM is already initialized in the same mode.
+// Every successful call must be balanced by a call to Uninitialize from the same goroutine.
+func Initialize() error {
+	runtime.LockOSThread()
+	if err := coInitializeEx(ole.COINIT_APARTMENTTHREADED); err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	return nil
+}
+
+// coInitializeEx initializes COM on the calling thread in the mode coinit and treats S_FALSE, i.e. COM being
+// already initialized in that mode, as success.
+func coInitializeEx(coinit uint32) error {
+	if err := ole.CoInitializeEx(0, coinit); err != nil {
+		if oleErr, ok := err.(*ole.OleError); !ok || oleErr.Code() != sFalse {
+			return err
+		}
+	}
+	return nil
+}
+
+// Uninitialize closes COM on the calling thread and unlocks the goroutine from it.
+// It must be called once for every successful call to Initialize, after all sessions created on the thread are closed.
+func Uninitialize() {
+	ole.CoUninitialize()
+	runtime.UnlockOSThread()
+}
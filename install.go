@@ -0,0 +1,204 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"context"
+)
+
+// InstallUpdates accepts the license terms of the updates and installs them. If the computer
+// must be restarted before the installation, ErrRebootRequired is returned before any license terms are
+// accepted and nothing is installed.
+func (iUpdateSession *IUpdateSession) InstallUpdates(updates []*IUpdate) (*IInstallationResult, error) {
+	installer, err := iUpdateSession.CreateUpdateInstaller()
+	if err != nil {
+		return nil, err
+	}
+
+	rebootRequired, err := installer.RebootRequiredBeforeInstallation()
+	if err != nil {
+		return nil, err
+	}
+	if rebootRequired {
+		return nil, ErrRebootRequired
+	}
+
+	for _, update := range updates {
+		if err := update.AcceptEula(); err != nil {
+			return nil, err
+		}
+	}
+
+	return installer.Install(updates)
+}
+
+// InstallProgress is a snapshot of the progress of an installation started by IUpdateInstaller.InstallWithProgress.
+type InstallProgress struct {
+	CurrentUpdateIndex           int32
+	CurrentUpdatePercentComplete int32
+	PercentComplete              int32
+}
+
+// InstallWithProgress installs the updates of the installer, see SetUpdates, in the background and reports its
+// progress on the first channel. The second channel receives the outcome once the installation is over: nil if
+// it succeeded, the error of the result otherwise, e.g. a *WUAError. The progress channel is closed before the
+// outcome is sent. Progress reports are dropped while the previous one has not been received. Cancelling ctx
+// aborts the installation.
+//
+// Like IUpdateDownloader.DownloadWithProgress, the installation runs on a thread of its own, with a new session
+// that takes over the ClientApplicationID of the installer and the WebProxy of its session, in which the updates
+// are searched for again by identity with the settings of the searcher that found them. See DownloadWithProgress
+// for the cost and the limits of that search. AllowSourcePrompts, ForceQuiet and IsForced are taken over from the
+// installer.
+func (iUpdateInstaller *IUpdateInstaller) InstallWithProgress(ctx context.Context) (<-chan InstallProgress, <-chan error) {
+	progressChan := make(chan InstallProgress, 1)
+	errChan := make(chan error, 1)
+
+	err := checkWritable(iUpdateInstaller.session)
+	if err == nil && len(iUpdateInstaller.Updates) == 0 {
+		err = ErrNoUpdatesSelected
+	}
+	if err != nil {
+		close(progressChan)
+		errChan <- err
+		close(errChan)
+		return progressChan, errChan
+	}
+	updates := iUpdateInstaller.Updates
+	allowSourcePrompts := iUpdateInstaller.AllowSourcePrompts
+	forceQuiet := iUpdateInstaller.ForceQuiet
+	isForced := iUpdateInstaller.IsForced
+	settings := iUpdateInstaller.session.settings(iUpdateInstaller.ClientApplicationID)
+
+	go func() {
+		err := func() error {
+			defer close(progressChan)
+
+			if err := Initialize(); err != nil {
+				return err
+			}
+			defer Uninitialize()
+
+			session, err := settings.newSession()
+			if err != nil {
+				return err
+			}
+			defer session.Close()
+
+			sessionUpdates, err := session.findUpdates(updates)
+			if err != nil {
+				return err
+			}
+
+			installer, err := session.CreateUpdateInstaller()
+			if err != nil {
+				return err
+			}
+			if err := installer.SetAllowSourcePrompts(allowSourcePrompts); err != nil {
+				return err
+			}
+			if err := installer.SetForceQuiet(forceQuiet); err != nil {
+				return err
+			}
+			if err := installer.SetIsForced(isForced); err != nil {
+				return err
+			}
+
+			job, err := installer.BeginInstall(ctx, sessionUpdates, func(progress *IInstallationProgress) {
+				select {
+				case progressChan <- InstallProgress{
+					CurrentUpdateIndex:           progress.CurrentUpdateIndex,
+					CurrentUpdatePercentComplete: progress.CurrentUpdatePercentComplete,
+					PercentComplete:              progress.PercentComplete,
+				}:
+				default:
+				}
+			})
+			if err != nil {
+				return err
+			}
+			result, err := installer.EndInstall(job)
+			if err != nil {
+				return err
+			}
+			return result.Err()
+		}()
+		errChan <- err
+		close(errChan)
+	}()
+
+	return progressChan, errChan
+}
+
+// InstallSequential installs updates one at a time, in order, and returns the result of each installation.
+// If stopOnReboot is true and an installation requires a reboot, see AnyRebootRequired, it stops there and
+// also returns the updates that are left to install after a restart. If an installation fails with an error,
+// it stops as well and the update that failed is the first of the remaining updates.
+func (iUpdateInstaller *IUpdateInstaller) InstallSequential(updates []*IUpdate, stopOnReboot bool) ([]*IInstallationResult, []*IUpdate, error) {
+	results := make([]*IInstallationResult, 0, len(updates))
+	for i, update := range updates {
+		result, err := iUpdateInstaller.Install([]*IUpdate{update})
+		if err != nil {
+			return results, updates[i:], err
+		}
+		results = append(results, result)
+
+		if stopOnReboot && iUpdateInstaller.AnyRebootRequired(result) {
+			return results, updates[i+1:], nil
+		}
+	}
+	return results, []*IUpdate{}, nil
+}
+
+// ValidationResult reports whether an update can be installed, see IUpdateInstaller.Validate.
+type ValidationResult struct {
+	Update          *IUpdate
+	Blockers        []error // the reasons the installation would fail, empty if it would not
+	DownloadSize    int64   // the maximum number of bytes left to download, 0 if the update is downloaded
+	IsUninstallable bool    // whether the update could be uninstalled again once installed
+}
+
+// Validate checks, without installing anything, whether updates could be installed by Install. The blockers of an
+// update are ErrRebootRequired if the computer must be restarted first, ErrEulaNotAccepted and ErrUpdateNotDownloaded.
+func (iUpdateInstaller *IUpdateInstaller) Validate(updates []*IUpdate) ([]ValidationResult, error) {
+	if err := checkWritable(iUpdateInstaller.session); err != nil {
+		return nil, err
+	}
+
+	rebootRequired, err := iUpdateInstaller.RebootRequiredBeforeInstallation()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ValidationResult, 0, len(updates))
+	for _, update := range updates {
+		result := ValidationResult{
+			Update:          update,
+			Blockers:        []error{},
+			IsUninstallable: update.IsUninstallable,
+		}
+		if rebootRequired {
+			result.Blockers = append(result.Blockers, ErrRebootRequired)
+		}
+		if !update.EulaAccepted {
+			result.Blockers = append(result.Blockers, ErrEulaNotAccepted)
+		}
+		if !update.IsDownloaded {
+			result.Blockers = append(result.Blockers, ErrUpdateNotDownloaded)
+			result.DownloadSize = update.MaxDownloadSize
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
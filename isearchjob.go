@@ -0,0 +1,54 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"context"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// ISearchJob represents an asynchronous search operation started by IUpdateSearcher.BeginSearch.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-isearchjob
+type ISearchJob struct {
+	disp        *ole.IDispatch
+	ctx         context.Context
+	cancel      context.CancelFunc
+	onCompleted *ole.IDispatch
+}
+
+// IsCompleted gets a Boolean value that indicates whether the call to IUpdateSearcher.BeginSearch is completely processed.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-isearchjob-get_iscompleted
+func (iSearchJob *ISearchJob) IsCompleted() (bool, error) {
+	return toBoolErr(oleutil.GetProperty(iSearchJob.disp, "IsCompleted"))
+}
+
+// RequestAbort makes a request to cancel the asynchronous search. Unlike the other methods of the job, it
+// may be called from any goroutine: the request is recorded and passed on to WUA by EndSearch, on the
+// thread that started the search.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-isearchjob-requestabort
+func (iSearchJob *ISearchJob) RequestAbort() {
+	iSearchJob.cancel()
+}
+
+// CleanUp waits for an asynchronous operation to complete and releases all the callbacks.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-isearchjob-cleanup
+func (iSearchJob *ISearchJob) CleanUp() error {
+	_, err := oleutil.CallMethod(iSearchJob.disp, "CleanUp")
+	releaseCallback(iSearchJob.onCompleted)
+	iSearchJob.onCompleted = nil
+	iSearchJob.cancel()
+	return wrapError(err)
+}
@@ -14,6 +14,8 @@ limitations under the License.
 package windowsupdate
 
 import (
+	"encoding/json"
+
 	"github.com/go-ole/go-ole"
 	"github.com/go-ole/go-ole/oleutil"
 )
@@ -22,9 +24,12 @@ import (
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-isearchresult
 type ISearchResult struct {
 	disp           *ole.IDispatch
-	ResultCode     int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-operationresultcode
+	session        *IUpdateSession
+	searchSettings *searchSettings
+	updatesDisp    *ole.IDispatch
+	updates        []*IUpdate // materialized by Updates
+	ResultCode     OperationResultCode
 	RootCategories []*ICategory
-	Updates        []*IUpdate
 	Warnings       []*IUpdateException
 }
 
@@ -34,9 +39,11 @@ func toISearchResult(searchResultDisp *ole.IDispatch) (*ISearchResult, error) {
 		disp: searchResultDisp,
 	}
 
-	if iSearchResult.ResultCode, err = toInt32Err(oleutil.GetProperty(searchResultDisp, "ResultCode")); err != nil {
+	resultCode, err := toInt32Err(oleutil.GetProperty(searchResultDisp, "ResultCode"))
+	if err != nil {
 		return nil, err
 	}
+	iSearchResult.ResultCode = OperationResultCode(resultCode)
 
 	rootCategoriesDisp, err := toIDispatchErr(oleutil.GetProperty(searchResultDisp, "RootCategories"))
 	if err != nil {
@@ -48,15 +55,9 @@ func toISearchResult(searchResultDisp *ole.IDispatch) (*ISearchResult, error) {
 		}
 	}
 
-	updatesDisp, err := toIDispatchErr(oleutil.GetProperty(searchResultDisp, "Updates"))
-	if err != nil {
+	if iSearchResult.updatesDisp, err = toIDispatchErr(oleutil.GetProperty(searchResultDisp, "Updates")); err != nil {
 		return nil, err
 	}
-	if updatesDisp != nil {
-		if iSearchResult.Updates, err = toIUpdates(updatesDisp); err != nil {
-			return nil, err
-		}
-	}
 
 	warningsDisp, err := toIDispatchErr(oleutil.GetProperty(searchResultDisp, "Warnings"))
 	if err != nil {
@@ -70,3 +71,75 @@ func toISearchResult(searchResultDisp *ole.IDispatch) (*ISearchResult, error) {
 
 	return iSearchResult, nil
 }
+
+// UpdateCount returns the number of updates found, without retrieving them.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatecollection-get_count
+func (iSearchResult *ISearchResult) UpdateCount() (int, error) {
+	if iSearchResult.updatesDisp == nil {
+		return 0, nil
+	}
+	count, err := toInt32Err(oleutil.GetProperty(iSearchResult.updatesDisp, "Count"))
+	return int(count), err
+}
+
+// Updates gets the updates found. They are retrieved on the first call rather than when the search
+// completes, since loading every update of a large result is expensive.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-isearchresult-get_updates
+func (iSearchResult *ISearchResult) Updates() ([]*IUpdate, error) {
+	if iSearchResult.updates != nil {
+		return iSearchResult.updates, nil
+	}
+	if iSearchResult.updatesDisp == nil {
+		return []*IUpdate{}, nil
+	}
+	updates, err := toIUpdates(iSearchResult.updatesDisp)
+	if err != nil {
+		return nil, err
+	}
+	setUpdatesSession(updates, iSearchResult.session)
+	for _, update := range updates {
+		update.searchSettings = iSearchResult.searchSettings
+	}
+	iSearchResult.updates = updates
+	return updates, nil
+}
+
+// MarshalJSON implements json.Marshaler. It emits the result code, the updates and the
+// warnings of the search and omits the COM handles.
+func (iSearchResult *ISearchResult) MarshalJSON() ([]byte, error) {
+	updates, err := iSearchResult.Updates()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		ResultCode OperationResultCode
+		Updates    []*IUpdate
+		Warnings   []*IUpdateException
+	}{
+		ResultCode: iSearchResult.ResultCode,
+		Updates:    updates,
+		Warnings:   iSearchResult.Warnings,
+	})
+}
+
+// Release releases the COM references held on the search result and its collection of updates.
+// The updates retrieved by Updates are not released, see ReleaseAll. Calling any method of the
+// search result after Release is a programming error.
+func (iSearchResult *ISearchResult) Release() {
+	if iSearchResult.updatesDisp != nil {
+		iSearchResult.updatesDisp.Release()
+		iSearchResult.updatesDisp = nil
+	}
+	if iSearchResult.disp != nil {
+		iSearchResult.disp.Release()
+		iSearchResult.disp = nil
+	}
+}
+
+// ReleaseAll releases the search result and every update retrieved by Updates, see IUpdate.Release.
+func (iSearchResult *ISearchResult) ReleaseAll() {
+	for _, update := range iSearchResult.updates {
+		update.Release()
+	}
+	iSearchResult.Release()
+}
@@ -1,3 +1,16 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
 package windowsupdate
 
 import (
@@ -5,14 +18,17 @@ import (
 	"github.com/go-ole/go-ole/oleutil"
 )
 
+// iStringCollectionToStringArrayErr reads an IStringCollection, such as IUpdate.KBArticleIDs or
+// IUpdate.MoreInfoUrls, into a []string by iterating its Count and Item properties.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-istringcollection
 func iStringCollectionToStringArrayErr(disp *ole.IDispatch, err error) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
 
+	// Return an empty rather than a nil slice, so that missing collections marshal to [] in JSON.
 	if disp == nil {
-		return nil, nil
+		return []string{}, nil
 	}
 
 	count, err := toInt32Err(oleutil.GetProperty(disp, "Count"))
@@ -32,3 +48,21 @@ func iStringCollectionToStringArrayErr(disp *ole.IDispatch, err error) ([]string
 	}
 	return stringCollection, nil
 }
+
+// stringArrayToIStringCollection creates an IStringCollection that contains the specified strings.
+func stringArrayToIStringCollection(strs []string) (*ole.IDispatch, error) {
+	unknown, err := oleutil.CreateObject("Microsoft.Update.StringColl")
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	disp, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for _, str := range strs {
+		if _, err := oleutil.CallMethod(disp, "Add", str); err != nil {
+			return nil, wrapError(err)
+		}
+	}
+	return disp, nil
+}
@@ -14,27 +14,41 @@ limitations under the License.
 package windowsupdate
 
 import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-ole/go-ole"
 	"github.com/go-ole/go-ole/oleutil"
 )
 
+// Interface IDs of the newer versions of IUpdate.
+var (
+	iidUpdate2 = ole.NewGUID("{144FE9B0-D23D-4A8B-8634-FB4457533B7A}")
+	iidUpdate3 = ole.NewGUID("{112EDA6B-95B3-476F-9D90-AEE82C6B8181}")
+	iidUpdate4 = ole.NewGUID("{27E94B0D-5139-49A2-9A61-93522DC54652}") // implemented from Windows 8 on
+)
+
 // IUpdate contains the properties and methods that are available to an update.
+// The fields are read when the update is loaded. The collections BundledUpdates, Categories and
+// DownloadContents are costly to load for every update of a search and are retrieved by methods instead.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdate
 type IUpdate struct {
 	disp                            *ole.IDispatch
+	session                         *IUpdateSession
+	searchSettings                  *searchSettings // nil if the update was not retrieved by a search
 	AutoSelectOnWebSites            bool
-	BundledUpdates                  []*IUpdateIdentity
+	BrowseOnly                      bool // false if the update does not implement IUpdate3
 	CanRequireSource                bool
-	Categories                      []*ICategory
-	Deadline                        *time.Time
+	CveIDs                          []string   // empty if the update does not implement IUpdate2
+	Deadline                        *time.Time // nil if the update has no deadline
 	DeltaCompressedContentAvailable bool
 	DeltaCompressedContentPreferred bool
-	DeploymentAction                int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-deploymentaction
+	DeploymentAction                DeploymentAction
 	Description                     string
-	DownloadContents                []*IUpdateDownloadContent
-	DownloadPriority                int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-downloadpriority
+	DownloadPriority                DownloadPriority
 	EulaAccepted                    bool
 	EulaText                        string
 	HandlerID                       string
@@ -46,7 +60,8 @@ type IUpdate struct {
 	IsHidden                        bool
 	IsInstalled                     bool
 	IsMandatory                     bool
-	IsUninstallable                 bool
+	IsPresent                       bool // false if the update does not implement IUpdate2
+	IsUninstallable                 bool // reported by WUA, which also takes UninstallationBehavior into account, see IUpdateInstaller.Uninstall
 	KBArticleIDs                    []string
 	Languages                       []string
 	LastDeploymentChangeTime        *time.Time
@@ -54,6 +69,8 @@ type IUpdate struct {
 	MinDownloadSize                 int64
 	MoreInfoUrls                    []string
 	MsrcSeverity                    string
+	PerUser                         bool // false if the update does not implement IUpdate4
+	RebootRequired                  bool // false if the update does not implement IUpdate2
 	RecommendedCpuSpeed             int32
 	RecommendedHardDiskSpace        int32
 	RecommendedMemory               int32
@@ -62,6 +79,7 @@ type IUpdate struct {
 	SupersededUpdateIDs             []string
 	SupportUrl                      string
 	Title                           string
+	Type                            UpdateType
 	UninstallationBehavior          *IInstallationBehavior
 	UninstallationNotes             string
 	UninstallationSteps             []string
@@ -90,38 +108,6 @@ func toIUpdates(updatesDisp *ole.IDispatch) ([]*IUpdate, error) {
 	return updates, nil
 }
 
-// toIUpdates takes a IUpdateCollection and returns the a
-// []*IUpdateIdentity of the contained IUpdates. This is *not* recursive, though possible should be
-func toIUpdatesIdentities(updatesDisp *ole.IDispatch) ([]*IUpdateIdentity, error) {
-	if updatesDisp == nil {
-		return nil, nil
-	}
-
-	count, err := toInt32Err(oleutil.GetProperty(updatesDisp, "Count"))
-	if err != nil {
-		return nil, err
-	}
-
-	identities := make([]*IUpdateIdentity, count)
-	for i := 0; i < int(count); i++ {
-		updateDisp, err := toIDispatchErr(oleutil.GetProperty(updatesDisp, "Item", i))
-		if err != nil {
-			return nil, err
-		}
-
-		identityDisp, err := toIDispatchErr(oleutil.GetProperty(updateDisp, "Identity"))
-		if err != nil {
-			return nil, err
-		}
-		if identityDisp != nil {
-			if identities[i], err = toIUpdateIdentity(identityDisp); err != nil {
-				return nil, err
-			}
-		}
-	}
-	return identities, nil
-}
-
 func toIUpdate(updateDisp *ole.IDispatch) (*IUpdate, error) {
 	var err error
 	iUpdate := &IUpdate{
@@ -132,12 +118,10 @@ func toIUpdate(updateDisp *ole.IDispatch) (*IUpdate, error) {
 		return nil, err
 	}
 
-	bundledUpdatesDisp, err := toIDispatchErr(oleutil.GetProperty(updateDisp, "BundledUpdates"))
-	if err != nil {
-		return nil, err
-	}
-	if bundledUpdatesDisp != nil {
-		if iUpdate.BundledUpdates, err = toIUpdatesIdentities(bundledUpdatesDisp); err != nil {
+	if update3Disp, err := updateDisp.QueryInterface(iidUpdate3); err == nil {
+		iUpdate.BrowseOnly, err = toBoolErr(oleutil.GetProperty(update3Disp, "BrowseOnly"))
+		update3Disp.Release()
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -146,12 +130,11 @@ func toIUpdate(updateDisp *ole.IDispatch) (*IUpdate, error) {
 		return nil, err
 	}
 
-	categoriesDisp, err := toIDispatchErr(oleutil.GetProperty(updateDisp, "Categories"))
-	if err != nil {
-		return nil, err
-	}
-	if categoriesDisp != nil {
-		if iUpdate.Categories, err = toICategories(categoriesDisp); err != nil {
+	iUpdate.CveIDs = []string{}
+	if update2Disp, err := updateDisp.QueryInterface(iidUpdate2); err == nil {
+		err = iUpdate.readUpdate2(update2Disp)
+		update2Disp.Release()
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -168,27 +151,21 @@ func toIUpdate(updateDisp *ole.IDispatch) (*IUpdate, error) {
 		return nil, err
 	}
 
-	if iUpdate.DeploymentAction, err = toInt32Err(oleutil.GetProperty(updateDisp, "DeploymentAction")); err != nil {
+	deploymentAction, err := toInt32Err(oleutil.GetProperty(updateDisp, "DeploymentAction"))
+	if err != nil {
 		return nil, err
 	}
+	iUpdate.DeploymentAction = DeploymentAction(deploymentAction)
 
 	if iUpdate.Description, err = toStringErr(oleutil.GetProperty(updateDisp, "Description")); err != nil {
 		return nil, err
 	}
 
-	downloadContentsDisp, err := toIDispatchErr(oleutil.GetProperty(updateDisp, "DownloadContents"))
+	downloadPriority, err := toInt32Err(oleutil.GetProperty(updateDisp, "DownloadPriority"))
 	if err != nil {
 		return nil, err
 	}
-	if downloadContentsDisp != nil {
-		if iUpdate.DownloadContents, err = toIUpdateDownloadContents(downloadContentsDisp); err != nil {
-			return nil, err
-		}
-	}
-
-	if iUpdate.DownloadPriority, err = toInt32Err(oleutil.GetProperty(updateDisp, "DownloadPriority")); err != nil {
-		return nil, err
-	}
+	iUpdate.DownloadPriority = DownloadPriority(downloadPriority)
 
 	if iUpdate.EulaAccepted, err = toBoolErr(oleutil.GetProperty(updateDisp, "EulaAccepted")); err != nil {
 		return nil, err
@@ -284,6 +261,14 @@ func toIUpdate(updateDisp *ole.IDispatch) (*IUpdate, error) {
 		return nil, err
 	}
 
+	if update4Disp, err := updateDisp.QueryInterface(iidUpdate4); err == nil {
+		iUpdate.PerUser, err = toBoolErr(oleutil.GetProperty(update4Disp, "PerUser"))
+		update4Disp.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if iUpdate.RecommendedCpuSpeed, err = toInt32Err(oleutil.GetProperty(updateDisp, "RecommendedCpuSpeed")); err != nil {
 		return nil, err
 	}
@@ -316,6 +301,12 @@ func toIUpdate(updateDisp *ole.IDispatch) (*IUpdate, error) {
 		return nil, err
 	}
 
+	updateType, err := toInt32Err(oleutil.GetProperty(updateDisp, "Type"))
+	if err != nil {
+		return nil, err
+	}
+	iUpdate.Type = UpdateType(updateType)
+
 	uninstallationBehaviorDisp, err := toIDispatchErr(oleutil.GetProperty(updateDisp, "UninstallationBehavior"))
 	if err != nil {
 		return nil, err
@@ -337,27 +328,212 @@ func toIUpdate(updateDisp *ole.IDispatch) (*IUpdate, error) {
 	return iUpdate, nil
 }
 
-func toIUpdateCollection(updates []*IUpdate) (*ole.IDispatch, error) {
-	unknown, err := oleutil.CreateObject("Microsoft.Update.UpdateColl")
-	if err != nil {
-		return nil, err
+// readUpdate2 reads the properties that were added by IUpdate2.
+func (iUpdate *IUpdate) readUpdate2(update2Disp *ole.IDispatch) error {
+	var err error
+	if iUpdate.CveIDs, err = iStringCollectionToStringArrayErr(toIDispatchErr(oleutil.GetProperty(update2Disp, "CveIDs"))); err != nil {
+		return err
+	}
+
+	if iUpdate.IsPresent, err = toBoolErr(oleutil.GetProperty(update2Disp, "IsPresent")); err != nil {
+		return err
+	}
+
+	iUpdate.RebootRequired, err = toBoolErr(oleutil.GetProperty(update2Disp, "RebootRequired"))
+	return err
+}
+
+// setUpdatesSession records the session that the updates were retrieved with.
+func setUpdatesSession(updates []*IUpdate, session *IUpdateSession) {
+	for _, update := range updates {
+		update.session = session
 	}
-	coll, err := unknown.QueryInterface(ole.IID_IDispatch)
+}
+
+func toIUpdateCollection(updates []*IUpdate) (*ole.IDispatch, error) {
+	coll, err := NewUpdateCollection()
 	if err != nil {
 		return nil, err
 	}
 	for _, update := range updates {
-		_, err := oleutil.CallMethod(coll, "Add", update.disp)
-		if err != nil {
+		if _, err := coll.Add(update); err != nil {
 			return nil, err
 		}
 	}
-	return coll, nil
+	return coll.disp, nil
 }
 
 // AcceptEula accepts the Microsoft Software License Terms that are associated with Windows Update. Administrators and power users can call this method.
+// It does nothing if the update has no license terms or they are already accepted, and returns
+// ErrReadOnlySession if the update was retrieved with a read-only session.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdate-accepteula
 func (iUpdate *IUpdate) AcceptEula() error {
-	_, err := oleutil.CallMethod(iUpdate.disp, "AcceptEula")
-	return err
+	if iUpdate.EulaAccepted {
+		return nil
+	}
+	if err := checkWritable(iUpdate.session); err != nil {
+		return err
+	}
+	if _, err := oleutil.CallMethod(iUpdate.disp, "AcceptEula"); err != nil {
+		return wrapError(err)
+	}
+	iUpdate.EulaAccepted = true
+	return nil
+}
+
+// SetIsHidden sets a Boolean value that hides the update from future search results, or shows it again.
+// Hidden updates are not installed by Automatic Updates. ErrReadOnlySession is returned if the update
+// was retrieved with a read-only session.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdate-put_ishidden
+func (iUpdate *IUpdate) SetIsHidden(isHidden bool) error {
+	if err := checkWritable(iUpdate.session); err != nil {
+		return err
+	}
+	if _, err := oleutil.PutProperty(iUpdate.disp, "IsHidden", isHidden); err != nil {
+		return wrapError(err)
+	}
+	iUpdate.IsHidden = isHidden
+	return nil
+}
+
+// htmlBreakTag matches an HTML tag that starts a new line in the description of an update.
+var htmlBreakTag = regexp.MustCompile(`(?i)<\s*/?\s*(br|p|div|li|ul|ol|tr|table|h[1-6])\b[^>]*>`)
+
+// htmlTag matches an HTML tag in the description of an update.
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// DescriptionText gets the Description property as plain text. Block and line-break HTML tags are
+// replaced with line breaks, the other tags are removed and the HTML entities are decoded.
+// The Description field is left untouched.
+func (iUpdate *IUpdate) DescriptionText() (string, error) {
+	description, err := toStringErr(oleutil.GetProperty(iUpdate.disp, "Description"))
+	if err != nil {
+		return "", err
+	}
+	return descriptionText(description), nil
+}
+
+func descriptionText(description string) string {
+	text := htmlBreakTag.ReplaceAllString(description, "\n")
+	text = html.UnescapeString(htmlTag.ReplaceAllString(text, ""))
+	lines := strings.Split(text, "\n")
+	nonEmpty := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			nonEmpty = append(nonEmpty, line)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
+
+// MarshalJSON implements json.Marshaler. It emits the identifying, sizing and severity
+// fields of the update and omits the COM handles and nested COM objects.
+func (iUpdate *IUpdate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Title                    string
+		Type                     UpdateType
+		Identity                 *IUpdateIdentity
+		Description              string
+		KBArticleIDs             []string
+		SecurityBulletinIDs      []string
+		MoreInfoUrls             []string
+		SupportUrl               string
+		MsrcSeverity             string
+		MaxDownloadSize          int64
+		MinDownloadSize          int64
+		Deadline                 *time.Time
+		LastDeploymentChangeTime *time.Time
+		IsDownloaded             bool
+		IsHidden                 bool
+		IsInstalled              bool
+		IsMandatory              bool
+		RebootRequired           bool
+	}{
+		Title:                    iUpdate.Title,
+		Type:                     iUpdate.Type,
+		Identity:                 iUpdate.Identity,
+		Description:              iUpdate.Description,
+		KBArticleIDs:             iUpdate.KBArticleIDs,
+		SecurityBulletinIDs:      iUpdate.SecurityBulletinIDs,
+		MoreInfoUrls:             iUpdate.MoreInfoUrls,
+		SupportUrl:               iUpdate.SupportUrl,
+		MsrcSeverity:             iUpdate.MsrcSeverity,
+		MaxDownloadSize:          iUpdate.MaxDownloadSize,
+		MinDownloadSize:          iUpdate.MinDownloadSize,
+		Deadline:                 iUpdate.Deadline,
+		LastDeploymentChangeTime: iUpdate.LastDeploymentChangeTime,
+		IsDownloaded:             iUpdate.IsDownloaded,
+		IsHidden:                 iUpdate.IsHidden,
+		IsInstalled:              iUpdate.IsInstalled,
+		IsMandatory:              iUpdate.IsMandatory,
+		RebootRequired:           iUpdate.RebootRequired,
+	})
+}
+
+// Release releases the COM reference held on the update before the garbage collector would.
+// Calling any method of the update after Release is a programming error, the fields read
+// when the update was loaded remain valid.
+func (iUpdate *IUpdate) Release() {
+	if iUpdate.disp != nil {
+		iUpdate.disp.Release()
+		iUpdate.disp = nil
+	}
+}
+
+// BundledUpdates gets the updates that are bundled with the update. They are retrieved on each call.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdate-get_bundledupdates
+func (iUpdate *IUpdate) BundledUpdates() ([]*IUpdate, error) {
+	bundledUpdatesDisp, err := toIDispatchErr(oleutil.GetProperty(iUpdate.disp, "BundledUpdates"))
+	if err != nil {
+		return nil, err
+	}
+	if bundledUpdatesDisp == nil {
+		return []*IUpdate{}, nil
+	}
+	bundledUpdates, err := toIUpdates(bundledUpdatesDisp)
+	if err != nil {
+		return nil, err
+	}
+	setUpdatesSession(bundledUpdates, iUpdate.session)
+	return bundledUpdates, nil
+}
+
+// Categories gets the categories that the update belongs to. They are retrieved on each call.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdate-get_categories
+func (iUpdate *IUpdate) Categories() ([]*ICategory, error) {
+	categoriesDisp, err := toIDispatchErr(oleutil.GetProperty(iUpdate.disp, "Categories"))
+	if err != nil {
+		return nil, err
+	}
+	if categoriesDisp == nil {
+		return []*ICategory{}, nil
+	}
+	return toICategories(categoriesDisp)
+}
+
+// DownloadContents gets the download contents of the update. They are retrieved on each call.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdate-get_downloadcontents
+func (iUpdate *IUpdate) DownloadContents() ([]*IUpdateDownloadContent, error) {
+	downloadContentsDisp, err := toIDispatchErr(oleutil.GetProperty(iUpdate.disp, "DownloadContents"))
+	if err != nil {
+		return nil, err
+	}
+	if downloadContentsDisp == nil {
+		return []*IUpdateDownloadContent{}, nil
+	}
+	return toIUpdateDownloadContents(downloadContentsDisp)
+}
+
+// DriverUpdate returns the driver specific properties of the update.
+// ErrNotDriverUpdate is returned if the update is not a driver update.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iwindowsdriverupdate
+func (iUpdate *IUpdate) DriverUpdate() (*IWindowsDriverUpdate, error) {
+	windowsDriverUpdateDisp, err := iUpdate.disp.QueryInterface(iidWindowsDriverUpdate)
+	if err != nil {
+		if err = wrapError(err); hresultOf(err) == hresultENoInterface {
+			return nil, ErrNotDriverUpdate
+		}
+		return nil, err
+	}
+	return toIWindowsDriverUpdate(windowsDriverUpdateDisp)
 }
@@ -0,0 +1,36 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import "testing"
+
+func TestDescriptionText(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"", ""},
+		{"Plain text.", "Plain text."},
+		{"Fixes &quot;Windows&quot; &amp; Office&#39;s issues.", `Fixes "Windows" & Office's issues.`},
+		{"First line<br>Second line<BR/>Third line", "First line\nSecond line\nThird line"},
+		{"<p>Summary</p><p>Details <b>in bold</b>.</p>", "Summary\nDetails in bold."},
+		{"<ul><li>One</li><li>Two</li></ul>", "One\nTwo"},
+		{"  Spaced\r\n   out  &lt;tag&gt;  ", "Spaced\nout <tag>"},
+	}
+	for _, test := range tests {
+		if got := descriptionText(test.description); got != test.want {
+			t.Errorf("descriptionText(%q) = %q, want %q", test.description, got, test.want)
+		}
+	}
+}
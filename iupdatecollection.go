@@ -0,0 +1,163 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IUpdateCollection represents an ordered list of updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdatecollection
+type IUpdateCollection struct {
+	disp    *ole.IDispatch
+	updates []*IUpdate // the updates added to the collection, in the same order
+}
+
+// NewUpdateCollection creates a new empty IUpdateCollection interface.
+func NewUpdateCollection() (*IUpdateCollection, error) {
+	unknown, err := oleutil.CreateObject("Microsoft.Update.UpdateColl")
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	disp, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &IUpdateCollection{
+		disp: disp,
+	}, nil
+}
+
+// Count gets the number of elements in the collection.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatecollection-get_count
+func (iUpdateCollection *IUpdateCollection) Count() (int32, error) {
+	return toInt32Err(oleutil.GetProperty(iUpdateCollection.disp, "Count"))
+}
+
+// Item gets the update at the specified index in the collection.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatecollection-get_item
+func (iUpdateCollection *IUpdateCollection) Item(index int32) (*IUpdate, error) {
+	count, err := iUpdateCollection.Count()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUpdateIndex(index, int(count)); err != nil {
+		return nil, err
+	}
+	return iUpdateCollection.item(index)
+}
+
+// item returns the update at index, which must be in range. Updates that were added with Add are returned as
+// they are, so they keep the session they were retrieved with.
+func (iUpdateCollection *IUpdateCollection) item(index int32) (*IUpdate, error) {
+	if int(index) < len(iUpdateCollection.updates) {
+		return iUpdateCollection.updates[index], nil
+	}
+	updateDisp, err := toIDispatchErr(oleutil.GetProperty(iUpdateCollection.disp, "Item", index))
+	if err != nil {
+		return nil, err
+	}
+	return toIUpdate(updateDisp)
+}
+
+// Add adds an update to the collection and returns the index at which it was added.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatecollection-add
+func (iUpdateCollection *IUpdateCollection) Add(update *IUpdate) (int32, error) {
+	index, err := toInt32Err(oleutil.CallMethod(iUpdateCollection.disp, "Add", update.disp))
+	if err != nil {
+		return 0, err
+	}
+	iUpdateCollection.updates = append(iUpdateCollection.updates, update)
+	return index, nil
+}
+
+// RemoveAt removes the update at the specified index from the collection.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatecollection-removeat
+func (iUpdateCollection *IUpdateCollection) RemoveAt(index int32) error {
+	if _, err := oleutil.CallMethod(iUpdateCollection.disp, "RemoveAt", index); err != nil {
+		return wrapError(err)
+	}
+	if index >= 0 && int(index) < len(iUpdateCollection.updates) {
+		iUpdateCollection.updates = append(iUpdateCollection.updates[:index:index], iUpdateCollection.updates[index+1:]...)
+	}
+	return nil
+}
+
+// Clear removes all the elements from the collection.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatecollection-clear
+func (iUpdateCollection *IUpdateCollection) Clear() error {
+	if _, err := oleutil.CallMethod(iUpdateCollection.disp, "Clear"); err != nil {
+		return wrapError(err)
+	}
+	iUpdateCollection.updates = nil
+	return nil
+}
+
+// Copy creates an independent copy of the collection, which can be modified without affecting the original.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatecollection-copy
+func (iUpdateCollection *IUpdateCollection) Copy() (*IUpdateCollection, error) {
+	copyDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateCollection.disp, "Copy"))
+	if err != nil {
+		return nil, err
+	}
+	return &IUpdateCollection{
+		disp:    copyDisp,
+		updates: append([]*IUpdate(nil), iUpdateCollection.updates...),
+	}, nil
+}
+
+// AcceptAllEulas accepts the license terms of every update in the collection, see IUpdate.AcceptEula.
+// It returns ErrReadOnlySession if an update was retrieved with a read-only session.
+func (iUpdateCollection *IUpdateCollection) AcceptAllEulas() error {
+	count, err := iUpdateCollection.Count()
+	if err != nil {
+		return err
+	}
+	updates := make([]*IUpdate, 0, count)
+	for i := int32(0); i < count; i++ {
+		update, err := iUpdateCollection.item(i)
+		if err != nil {
+			return err
+		}
+		if err := checkWritable(update.session); err != nil {
+			return err
+		}
+		updates = append(updates, update)
+	}
+	for _, update := range updates {
+		if err := update.AcceptEula(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Release releases the COM reference held on the collection. The updates added to the collection
+// are not released, see ReleaseAll. Calling any method of the collection after Release is a programming error.
+func (iUpdateCollection *IUpdateCollection) Release() {
+	if iUpdateCollection.disp != nil {
+		iUpdateCollection.disp.Release()
+		iUpdateCollection.disp = nil
+	}
+}
+
+// ReleaseAll releases every update added to the collection, see IUpdate.Release, and then the collection.
+func (iUpdateCollection *IUpdateCollection) ReleaseAll() {
+	for _, update := range iUpdateCollection.updates {
+		update.Release()
+	}
+	iUpdateCollection.updates = nil
+	iUpdateCollection.Release()
+}
@@ -0,0 +1,109 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"testing"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+func TestSetUpdatesRoundTrip(t *testing.T) {
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer Uninitialize()
+
+	session, err := NewUpdateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	searcher, err := session.CreateUpdateSearcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := searcher.SetOnline(false); err != nil {
+		t.Fatal(err)
+	}
+	result, err := searcher.Search("IsInstalled=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates, err := result.Updates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) > 2 {
+		updates = updates[:2]
+	}
+
+	collection, err := NewUpdateCollection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer collection.Release()
+	for _, update := range updates {
+		if _, err := collection.Add(update); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	downloader, err := session.CreateUpdateDownloader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := downloader.SetUpdates(collection); err != nil {
+		t.Fatal(err)
+	}
+	checkUpdatesRoundTrip(t, "IUpdateDownloader", downloader.disp, downloader.Updates, updates)
+
+	installer, err := session.CreateUpdateInstaller()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := installer.SetUpdates(collection); err != nil {
+		t.Fatal(err)
+	}
+	checkUpdatesRoundTrip(t, "IUpdateInstaller", installer.disp, installer.Updates, updates)
+}
+
+func checkUpdatesRoundTrip(t *testing.T, name string, disp *ole.IDispatch, got []*IUpdate, want []*IUpdate) {
+	t.Helper()
+
+	updatesDisp, err := toIDispatchErr(oleutil.GetProperty(disp, "Updates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := toInt32Err(oleutil.GetProperty(updatesDisp, "Count"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(count) != len(want) {
+		t.Errorf("%s.Updates.Count = %d, want %d", name, count, len(want))
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%s: len(Updates) = %d, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s: Updates[%d] is not the update added to the collection", name, i)
+		}
+	}
+}
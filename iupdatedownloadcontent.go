@@ -15,6 +15,7 @@ package windowsupdate
 
 import (
 	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
 )
 
 // IUpdateDownloadContent represents the download content of an update.
@@ -25,6 +26,37 @@ type IUpdateDownloadContent struct {
 }
 
 func toIUpdateDownloadContents(updateDownloadContentsDisp *ole.IDispatch) ([]*IUpdateDownloadContent, error) {
-	// TODO
-	return nil, nil
+	count, err := toInt32Err(oleutil.GetProperty(updateDownloadContentsDisp, "Count"))
+	if err != nil {
+		return nil, err
+	}
+
+	updateDownloadContents := make([]*IUpdateDownloadContent, 0, count)
+	for i := 0; i < int(count); i++ {
+		updateDownloadContentDisp, err := toIDispatchErr(oleutil.GetProperty(updateDownloadContentsDisp, "Item", i))
+		if err != nil {
+			return nil, err
+		}
+
+		updateDownloadContent, err := toIUpdateDownloadContent(updateDownloadContentDisp)
+		if err != nil {
+			return nil, err
+		}
+
+		updateDownloadContents = append(updateDownloadContents, updateDownloadContent)
+	}
+	return updateDownloadContents, nil
+}
+
+func toIUpdateDownloadContent(updateDownloadContentDisp *ole.IDispatch) (*IUpdateDownloadContent, error) {
+	var err error
+	iUpdateDownloadContent := &IUpdateDownloadContent{
+		disp: updateDownloadContentDisp,
+	}
+
+	if iUpdateDownloadContent.DownloadUrl, err = toStringErr(oleutil.GetProperty(updateDownloadContentDisp, "DownloadUrl")); err != nil {
+		return nil, err
+	}
+
+	return iUpdateDownloadContent, nil
 }
@@ -14,6 +14,8 @@ limitations under the License.
 package windowsupdate
 
 import (
+	"context"
+
 	"github.com/go-ole/go-ole"
 	"github.com/go-ole/go-ole/oleutil"
 )
@@ -22,9 +24,9 @@ import (
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdatedownloaders
 type IUpdateDownloader struct {
 	disp                *ole.IDispatch
+	session             *IUpdateSession
 	ClientApplicationID string
 	IsForced            bool
-	Priority            int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-downloadpriority
 	Updates             []*IUpdate
 }
 
@@ -42,10 +44,6 @@ func toIUpdateDownloader(updateDownloaderDisp *ole.IDispatch) (*IUpdateDownloade
 		return nil, err
 	}
 
-	if iUpdateDownloader.Priority, err = toInt32Err(oleutil.GetProperty(updateDownloaderDisp, "Priority")); err != nil {
-		return nil, err
-	}
-
 	updatesDisp, err := toIDispatchErr(oleutil.GetProperty(updateDownloaderDisp, "Updates"))
 	if err != nil {
 		return nil, err
@@ -59,20 +57,149 @@ func toIUpdateDownloader(updateDownloaderDisp *ole.IDispatch) (*IUpdateDownloade
 	return iUpdateDownloader, nil
 }
 
+// Priority gets the priority level of the download.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatedownloader-get_priority
+func (iUpdateDownloader *IUpdateDownloader) Priority() (DownloadPriority, error) {
+	priority, err := toInt32Err(oleutil.GetProperty(iUpdateDownloader.disp, "Priority"))
+	return DownloadPriority(priority), err
+}
+
+// SetPriority sets the priority level of the download, e.g. DownloadPriorityDpLow to limit the bandwidth used on slow links.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatedownloader-put_priority
+func (iUpdateDownloader *IUpdateDownloader) SetPriority(priority DownloadPriority) error {
+	_, err := oleutil.PutProperty(iUpdateDownloader.disp, "Priority", int32(priority))
+	return wrapError(err)
+}
+
+// SetIsForced sets a Boolean value that indicates whether updates are downloaded again even if they are already downloaded.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatedownloader-put_isforced
+func (iUpdateDownloader *IUpdateDownloader) SetIsForced(isForced bool) error {
+	if _, err := oleutil.PutProperty(iUpdateDownloader.disp, "IsForced", isForced); err != nil {
+		return wrapError(err)
+	}
+	iUpdateDownloader.IsForced = isForced
+	return nil
+}
+
+// SetClientApplicationID sets the identifier of the current client application, which is recorded in the update history.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatedownloader-put_clientapplicationid
+func (iUpdateDownloader *IUpdateDownloader) SetClientApplicationID(clientApplicationID string) error {
+	if _, err := oleutil.PutProperty(iUpdateDownloader.disp, "ClientApplicationID", clientApplicationID); err != nil {
+		return wrapError(err)
+	}
+	iUpdateDownloader.ClientApplicationID = clientApplicationID
+	return nil
+}
+
+// SetUpdates sets the collection of updates to be downloaded.
+// Updates is set to the updates added to the collection, which keep the session they were retrieved with.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatedownloader-put_updates
+func (iUpdateDownloader *IUpdateDownloader) SetUpdates(updates *IUpdateCollection) error {
+	if _, err := oleutil.PutProperty(iUpdateDownloader.disp, "Updates", updates.disp); err != nil {
+		return wrapError(err)
+	}
+	iUpdateDownloader.Updates = append([]*IUpdate(nil), updates.updates...)
+	return nil
+}
+
 // Download starts a synchronous download of the content files that are associated with the updates.
+// ErrNoUpdatesSelected is returned if updates is empty.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatedownloader-download
 func (iUpdateDownloader *IUpdateDownloader) Download(updates []*IUpdate) (*IDownloadResult, error) {
+	if err := checkWritable(iUpdateDownloader.session); err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return nil, ErrNoUpdatesSelected
+	}
+
 	updatesDisp, err := toIUpdateCollection(updates)
 	if err != nil {
 		return nil, err
 	}
 	if _, err = oleutil.PutProperty(iUpdateDownloader.disp, "Updates", updatesDisp); err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 
 	downloadResultDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateDownloader.disp, "Download"))
 	if err != nil {
 		return nil, err
 	}
-	return toIDownloadResult(downloadResultDisp)
+	return toIDownloadResult(downloadResultDisp, updates)
+}
+
+// BeginDownload starts an asynchronous download of the content files that are associated with the updates.
+// If onProgress is not nil, it is called each time the progress of the download changes.
+// The job must be passed to EndDownload, which waits for the download to complete. Cancelling ctx makes
+// EndDownload request the job to abort.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatedownloader-begindownload
+func (iUpdateDownloader *IUpdateDownloader) BeginDownload(ctx context.Context, updates []*IUpdate, onProgress func(*IDownloadProgress)) (*IDownloadJob, error) {
+	if err := checkWritable(iUpdateDownloader.session); err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return nil, ErrNoUpdatesSelected
+	}
+
+	updatesDisp, err := toIUpdateCollection(updates)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = oleutil.PutProperty(iUpdateDownloader.disp, "Updates", updatesDisp); err != nil {
+		return nil, wrapError(err)
+	}
+
+	onProgressChanged := newCallback(iidDownloadProgressChangedCallback, func(job *ole.IDispatch, args *ole.IDispatch) {
+		if onProgress == nil {
+			return
+		}
+		downloadProgressDisp, err := toIDispatchErr(oleutil.GetProperty(args, "Progress"))
+		if err != nil || downloadProgressDisp == nil {
+			return
+		}
+		downloadProgress, err := toIDownloadProgress(downloadProgressDisp)
+		if err != nil {
+			return
+		}
+		onProgress(downloadProgress)
+	})
+	onCompleted := newCallback(iidDownloadCompletedCallback, nil)
+
+	downloadJobDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateDownloader.disp, "BeginDownload", onProgressChanged, onCompleted, nil))
+	if err != nil {
+		releaseCallback(onProgressChanged)
+		releaseCallback(onCompleted)
+		return nil, err
+	}
+
+	iDownloadJob, err := toIDownloadJob(downloadJobDisp)
+	if err != nil {
+		releaseCallback(onProgressChanged)
+		releaseCallback(onCompleted)
+		return nil, err
+	}
+	iDownloadJob.ctx = ctx
+	iDownloadJob.onProgressChanged = onProgressChanged
+	iDownloadJob.onCompleted = onCompleted
+	iDownloadJob.updates = updates
+	return iDownloadJob, nil
+}
+
+// EndDownload waits for an asynchronous download started by BeginDownload to complete and returns its result.
+// While waiting, progress callbacks are delivered on the calling thread, which must be the thread that called
+// BeginDownload. If the job's context is done first, the download is aborted and the context's error is returned.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatedownloader-enddownload
+func (iUpdateDownloader *IUpdateDownloader) EndDownload(downloadJob *IDownloadJob) (*IDownloadResult, error) {
+	defer downloadJob.CleanUp()
+
+	waitErr := waitForJob(downloadJob.ctx, downloadJob.disp)
+
+	downloadResultDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateDownloader.disp, "EndDownload", downloadJob.disp))
+	if waitErr != nil {
+		return nil, waitErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toIDownloadResult(downloadResultDisp, downloadJob.updates)
 }
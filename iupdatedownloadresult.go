@@ -20,12 +20,19 @@ import (
 // IUpdateDownloadResult contains the properties that indicate the status of a download operation for an update.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdatedownloadresult
 type IUpdateDownloadResult struct {
-	disp       *ole.IDispatch
-	HResult    int32
-	ResultCode int32
+	disp *ole.IDispatch
+	OperationResult
 }
 
 func toIUpdateDownloadResult(iUpdateDownloadResultDisp *ole.IDispatch) (*IUpdateDownloadResult, error) {
-	// TODO
-	return nil, nil
+	var err error
+	iUpdateDownloadResult := &IUpdateDownloadResult{
+		disp: iUpdateDownloadResultDisp,
+	}
+
+	if iUpdateDownloadResult.OperationResult, err = toOperationResult(iUpdateDownloadResultDisp); err != nil {
+		return nil, err
+	}
+
+	return iUpdateDownloadResult, nil
 }
@@ -15,18 +15,62 @@ package windowsupdate
 
 import (
 	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
 )
 
 // IUpdateException represents info about the aspects of search results returned in the ISearchResult object that were incomplete. For more info, see Remarks.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdateexception
 type IUpdateException struct {
 	disp    *ole.IDispatch
-	Context int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-updateexceptioncontext
+	Context UpdateExceptionContext
 	HResult int64
 	Message string
 }
 
 func toIUpdateExceptions(updateExceptionsDisp *ole.IDispatch) ([]*IUpdateException, error) {
-	// TODO
-	return nil, nil
+	count, err := toInt32Err(oleutil.GetProperty(updateExceptionsDisp, "Count"))
+	if err != nil {
+		return nil, err
+	}
+
+	updateExceptions := make([]*IUpdateException, 0, count)
+	for i := 0; i < int(count); i++ {
+		updateExceptionDisp, err := toIDispatchErr(oleutil.GetProperty(updateExceptionsDisp, "Item", i))
+		if err != nil {
+			return nil, err
+		}
+
+		updateException, err := toIUpdateException(updateExceptionDisp)
+		if err != nil {
+			return nil, err
+		}
+
+		updateExceptions = append(updateExceptions, updateException)
+	}
+	return updateExceptions, nil
+}
+
+func toIUpdateException(updateExceptionDisp *ole.IDispatch) (*IUpdateException, error) {
+	iUpdateException := &IUpdateException{
+		disp: updateExceptionDisp,
+	}
+
+	exceptionContext, err := toInt32Err(oleutil.GetProperty(updateExceptionDisp, "Context"))
+	if err != nil {
+		return nil, err
+	}
+	iUpdateException.Context = UpdateExceptionContext(exceptionContext)
+
+	// HResult is a VT_I4, as are the HRESULTs of the other WUA interfaces.
+	hResult, err := toInt32Err(oleutil.GetProperty(updateExceptionDisp, "HResult"))
+	if err != nil {
+		return nil, err
+	}
+	iUpdateException.HResult = int64(hResult)
+
+	if iUpdateException.Message, err = toStringErr(oleutil.GetProperty(updateExceptionDisp, "Message")); err != nil {
+		return nil, err
+	}
+
+	return iUpdateException, nil
 }
@@ -25,12 +25,11 @@ import (
 type IUpdateHistoryEntry struct {
 	disp                *ole.IDispatch
 	ClientApplicationID string
-	Date                *time.Time
+	Date                *time.Time // in UTC
 	Description         string
-	HResult             int32
+	OperationResult
 	Operation           int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-updateoperation
-	ResultCode          int32 // enum https://docs.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-operationresultcode
-	ServerSelection     int32 // enum
+	ServerSelection     ServerSelection
 	ServiceID           string
 	SupportUrl          string
 	Title               string
@@ -81,7 +80,7 @@ func toIUpdateHistoryEntry(updateHistoryEntryDisp *ole.IDispatch) (*IUpdateHisto
 		return nil, err
 	}
 
-	if iUpdateHistoryEntry.HResult, err = toInt32Err(oleutil.GetProperty(updateHistoryEntryDisp, "HResult")); err != nil {
+	if iUpdateHistoryEntry.OperationResult, err = toOperationResult(updateHistoryEntryDisp); err != nil {
 		return nil, err
 	}
 
@@ -89,13 +88,11 @@ func toIUpdateHistoryEntry(updateHistoryEntryDisp *ole.IDispatch) (*IUpdateHisto
 		return nil, err
 	}
 
-	if iUpdateHistoryEntry.ResultCode, err = toInt32Err(oleutil.GetProperty(updateHistoryEntryDisp, "ResultCode")); err != nil {
-		return nil, err
-	}
-
-	if iUpdateHistoryEntry.ServerSelection, err = toInt32Err(oleutil.GetProperty(updateHistoryEntryDisp, "ServerSelection")); err != nil {
+	serverSelection, err := toInt32Err(oleutil.GetProperty(updateHistoryEntryDisp, "ServerSelection"))
+	if err != nil {
 		return nil, err
 	}
+	iUpdateHistoryEntry.ServerSelection = ServerSelection(serverSelection)
 
 	if iUpdateHistoryEntry.ServiceID, err = toStringErr(oleutil.GetProperty(updateHistoryEntryDisp, "ServiceID")); err != nil {
 		return nil, err
@@ -0,0 +1,44 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IUpdateInstallationResult represents the result of an installation or uninstallation of an update.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdateinstallationresult
+type IUpdateInstallationResult struct {
+	disp *ole.IDispatch
+	OperationResult
+	RebootRequired bool
+}
+
+func toIUpdateInstallationResult(updateInstallationResultDisp *ole.IDispatch) (*IUpdateInstallationResult, error) {
+	var err error
+	iUpdateInstallationResult := &IUpdateInstallationResult{
+		disp: updateInstallationResultDisp,
+	}
+
+	if iUpdateInstallationResult.OperationResult, err = toOperationResult(updateInstallationResultDisp); err != nil {
+		return nil, err
+	}
+
+	if iUpdateInstallationResult.RebootRequired, err = toBoolErr(oleutil.GetProperty(updateInstallationResultDisp, "RebootRequired")); err != nil {
+		return nil, err
+	}
+
+	return iUpdateInstallationResult, nil
+}
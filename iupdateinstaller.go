@@ -14,6 +14,9 @@ limitations under the License.
 package windowsupdate
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/go-ole/go-ole"
 	"github.com/go-ole/go-ole/oleutil"
 )
@@ -22,14 +25,14 @@ import (
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdateinstaller
 type IUpdateInstaller struct {
 	disp                *ole.IDispatch
+	session             *IUpdateSession
 	AllowSourcePrompts  bool
 	ClientApplicationID string
-	IsBusy              bool
+	ForceQuiet          bool
 	IsForced            bool
 	// ParentHwnd                       HWND
 	// ParentWindow                     IUnknown
-	RebootRequiredBeforeInstallation bool
-	Updates                          []*IUpdate
+	Updates []*IUpdate
 }
 
 func toIUpdateInstaller(updateInstallerDisp *ole.IDispatch) (*IUpdateInstaller, error) {
@@ -46,7 +49,7 @@ func toIUpdateInstaller(updateInstallerDisp *ole.IDispatch) (*IUpdateInstaller,
 		return nil, err
 	}
 
-	if iUpdateInstaller.IsBusy, err = toBoolErr(oleutil.GetProperty(updateInstallerDisp, "IsBusy")); err != nil {
+	if iUpdateInstaller.ForceQuiet, err = toBoolErr(oleutil.GetProperty(updateInstallerDisp, "ForceQuiet")); err != nil {
 		return nil, err
 	}
 
@@ -54,10 +57,6 @@ func toIUpdateInstaller(updateInstallerDisp *ole.IDispatch) (*IUpdateInstaller,
 		return nil, err
 	}
 
-	if iUpdateInstaller.RebootRequiredBeforeInstallation, err = toBoolErr(oleutil.GetProperty(updateInstallerDisp, "RebootRequiredBeforeInstallation")); err != nil {
-		return nil, err
-	}
-
 	updatesDisp, err := toIDispatchErr(oleutil.GetProperty(updateInstallerDisp, "Updates"))
 	if err != nil {
 		return nil, err
@@ -71,20 +70,244 @@ func toIUpdateInstaller(updateInstallerDisp *ole.IDispatch) (*IUpdateInstaller,
 	return iUpdateInstaller, nil
 }
 
+// IsBusy gets a Boolean value that indicates whether an installation or uninstallation is in progress on the computer.
+// The value is read from WUA on every call.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-get_isbusy
+func (iUpdateInstaller *IUpdateInstaller) IsBusy() (bool, error) {
+	return toBoolErr(oleutil.GetProperty(iUpdateInstaller.disp, "IsBusy"))
+}
+
+// RebootRequiredBeforeInstallation gets a Boolean value that indicates whether a system restart is required before installing or uninstalling updates.
+// The value is read from WUA on every call.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-get_rebootrequiredbeforeinstallation
+func (iUpdateInstaller *IUpdateInstaller) RebootRequiredBeforeInstallation() (bool, error) {
+	return toBoolErr(oleutil.GetProperty(iUpdateInstaller.disp, "RebootRequiredBeforeInstallation"))
+}
+
+// SetAllowSourcePrompts sets a Boolean value that indicates whether to show source prompts to the user when installing or uninstalling an update requires its source media.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-put_allowsourceprompts
+func (iUpdateInstaller *IUpdateInstaller) SetAllowSourcePrompts(allowSourcePrompts bool) error {
+	if _, err := oleutil.PutProperty(iUpdateInstaller.disp, "AllowSourcePrompts", allowSourcePrompts); err != nil {
+		return wrapError(err)
+	}
+	iUpdateInstaller.AllowSourcePrompts = allowSourcePrompts
+	return nil
+}
+
+// SetForceQuiet sets a Boolean value that indicates whether the installer suppresses all user interface during the installation.
+// Installations are only forced to be quiet when no user is logged on to the computer.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller2-put_forcequiet
+func (iUpdateInstaller *IUpdateInstaller) SetForceQuiet(forceQuiet bool) error {
+	if _, err := oleutil.PutProperty(iUpdateInstaller.disp, "ForceQuiet", forceQuiet); err != nil {
+		return wrapError(err)
+	}
+	iUpdateInstaller.ForceQuiet = forceQuiet
+	return nil
+}
+
+// SetIsForced sets a Boolean value that indicates whether updates that are already installed are reinstalled, or updates that are not installed are uninstalled anyway.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-put_isforced
+func (iUpdateInstaller *IUpdateInstaller) SetIsForced(isForced bool) error {
+	if _, err := oleutil.PutProperty(iUpdateInstaller.disp, "IsForced", isForced); err != nil {
+		return wrapError(err)
+	}
+	iUpdateInstaller.IsForced = isForced
+	return nil
+}
+
+// SetClientApplicationID sets the identifier of the current client application, which is recorded in the update history.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-put_clientapplicationid
+func (iUpdateInstaller *IUpdateInstaller) SetClientApplicationID(clientApplicationID string) error {
+	if _, err := oleutil.PutProperty(iUpdateInstaller.disp, "ClientApplicationID", clientApplicationID); err != nil {
+		return wrapError(err)
+	}
+	iUpdateInstaller.ClientApplicationID = clientApplicationID
+	return nil
+}
+
+// SetUpdates sets the collection of updates to be installed.
+// Updates is set to the updates added to the collection, which keep the session they were retrieved with.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-put_updates
+func (iUpdateInstaller *IUpdateInstaller) SetUpdates(updates *IUpdateCollection) error {
+	if _, err := oleutil.PutProperty(iUpdateInstaller.disp, "Updates", updates.disp); err != nil {
+		return wrapError(err)
+	}
+	iUpdateInstaller.Updates = append([]*IUpdate(nil), updates.updates...)
+	return nil
+}
+
 // Install starts a synchronous installation of the updates.
+// ErrNoUpdatesSelected is returned if updates is empty.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-install
 func (iUpdateInstaller *IUpdateInstaller) Install(updates []*IUpdate) (*IInstallationResult, error) {
+	if err := checkWritable(iUpdateInstaller.session); err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return nil, ErrNoUpdatesSelected
+	}
+
 	updatesDisp, err := toIUpdateCollection(updates)
 	if err != nil {
 		return nil, err
 	}
 	if _, err = oleutil.PutProperty(iUpdateInstaller.disp, "Updates", updatesDisp); err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 
 	installationResultDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateInstaller.disp, "Install"))
 	if err != nil {
 		return nil, err
 	}
-	return toIInstallationResult(installationResultDisp)
+	return toIInstallationResult(installationResultDisp, updates)
+}
+
+// Uninstall starts a synchronous uninstallation of the updates.
+// If any of the updates cannot be uninstalled, an error wrapping ErrUpdateNotUninstallable is returned and nothing is uninstalled.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-uninstall
+func (iUpdateInstaller *IUpdateInstaller) Uninstall(updates []*IUpdate) (*IInstallationResult, error) {
+	if err := checkWritable(iUpdateInstaller.session); err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return nil, ErrNoUpdatesSelected
+	}
+
+	for _, update := range updates {
+		if !update.IsUninstallable {
+			return nil, fmt.Errorf("%w: %s", ErrUpdateNotUninstallable, update.Title)
+		}
+	}
+
+	updatesDisp, err := toIUpdateCollection(updates)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = oleutil.PutProperty(iUpdateInstaller.disp, "Updates", updatesDisp); err != nil {
+		return nil, wrapError(err)
+	}
+
+	installationResultDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateInstaller.disp, "Uninstall"))
+	if err != nil {
+		return nil, err
+	}
+	return toIInstallationResult(installationResultDisp, updates)
+}
+
+// BeginInstall starts an asynchronous installation of the updates.
+// If onProgress is not nil, it is called each time the progress of the installation changes.
+// The job must be passed to EndInstall, which waits for the installation to complete. Cancelling ctx makes
+// EndInstall request the job to abort.
+//
+// WUA objects live in the COM apartment of the thread that created them. BeginInstall and EndInstall must be
+// called from that thread (lock the goroutine with runtime.LockOSThread), and onProgress runs on it as well,
+// so it may use other objects of this package but must not block for long.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-begininstall
+func (iUpdateInstaller *IUpdateInstaller) BeginInstall(ctx context.Context, updates []*IUpdate, onProgress func(*IInstallationProgress)) (*IInstallationJob, error) {
+	if err := checkWritable(iUpdateInstaller.session); err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return nil, ErrNoUpdatesSelected
+	}
+
+	updatesDisp, err := toIUpdateCollection(updates)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = oleutil.PutProperty(iUpdateInstaller.disp, "Updates", updatesDisp); err != nil {
+		return nil, wrapError(err)
+	}
+
+	onProgressChanged := newCallback(iidInstallationProgressChangedCallback, func(job *ole.IDispatch, args *ole.IDispatch) {
+		if onProgress == nil {
+			return
+		}
+		installationProgressDisp, err := toIDispatchErr(oleutil.GetProperty(args, "Progress"))
+		if err != nil || installationProgressDisp == nil {
+			return
+		}
+		installationProgress, err := toIInstallationProgress(installationProgressDisp)
+		if err != nil {
+			return
+		}
+		onProgress(installationProgress)
+	})
+	onCompleted := newCallback(iidInstallationCompletedCallback, nil)
+
+	installationJobDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateInstaller.disp, "BeginInstall", onProgressChanged, onCompleted, nil))
+	if err != nil {
+		releaseCallback(onProgressChanged)
+		releaseCallback(onCompleted)
+		return nil, err
+	}
+
+	iInstallationJob, err := toIInstallationJob(installationJobDisp)
+	if err != nil {
+		releaseCallback(onProgressChanged)
+		releaseCallback(onCompleted)
+		return nil, err
+	}
+	iInstallationJob.ctx = ctx
+	iInstallationJob.onProgressChanged = onProgressChanged
+	iInstallationJob.onCompleted = onCompleted
+	iInstallationJob.updates = updates
+	return iInstallationJob, nil
+}
+
+// EndInstall waits for an asynchronous installation started by BeginInstall to complete and returns its result.
+// While waiting, progress callbacks are delivered on the calling thread, which must be the thread that called
+// BeginInstall. If the job's context is done first, the installation is aborted and the context's error is returned.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateinstaller-endinstall
+func (iUpdateInstaller *IUpdateInstaller) EndInstall(installationJob *IInstallationJob) (*IInstallationResult, error) {
+	defer installationJob.CleanUp()
+
+	waitErr := waitForJob(installationJob.ctx, installationJob.disp)
+
+	installationResultDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateInstaller.disp, "EndInstall", installationJob.disp))
+	if waitErr != nil {
+		return nil, waitErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toIInstallationResult(installationResultDisp, installationJob.updates)
+}
+
+// AnyRebootRequired reports whether result, returned by an installation or uninstallation of this installer,
+// requires a reboot. Some updates report RebootRequired only on their own IUpdateInstallationResult and not on
+// the aggregate result, so the result of each update of the operation is checked as well. If the result of an
+// update cannot be read, true is returned, so that a pending reboot is never hidden.
+func (iUpdateInstaller *IUpdateInstaller) AnyRebootRequired(result *IInstallationResult) bool {
+	if result.RebootRequired {
+		return true
+	}
+
+	for i := range result.updates {
+		updateResult, err := result.GetUpdateResult(int32(i))
+		if err != nil {
+			return true
+		}
+		if updateResult.RebootRequired {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPendingReboot reports whether the computer has a pending reboot, either because the installer requires one
+// before installing, see RebootRequiredBeforeInstallation, or because an earlier installation or uninstallation
+// reported by ISystemInformation.RebootRequired has not been completed by a restart yet.
+func (iUpdateInstaller *IUpdateInstaller) IsPendingReboot() (bool, error) {
+	rebootRequired, err := iUpdateInstaller.RebootRequiredBeforeInstallation()
+	if err != nil || rebootRequired {
+		return rebootRequired, err
+	}
+
+	systemInformation, err := NewSystemInformation()
+	if err != nil {
+		return false, err
+	}
+	defer systemInformation.disp.Release()
+	return systemInformation.RebootRequired()
 }
@@ -14,6 +14,8 @@ limitations under the License.
 package windowsupdate
 
 import (
+	"context"
+
 	"github.com/go-ole/go-ole"
 	"github.com/go-ole/go-ole/oleutil"
 )
@@ -22,11 +24,12 @@ import (
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdatesearcher
 type IUpdateSearcher struct {
 	disp                                *ole.IDispatch
+	session                             *IUpdateSession
 	CanAutomaticallyUpgradeService      bool
 	ClientApplicationID                 string
 	IncludePotentiallySupersededUpdates bool
 	Online                              bool
-	ServerSelection                     int32
+	ServerSelection                     ServerSelection
 	ServiceID                           string
 }
 
@@ -52,9 +55,11 @@ func toIUpdateSearcher(updateSearcherDisp *ole.IDispatch) (*IUpdateSearcher, err
 		return nil, err
 	}
 
-	if iUpdateSearcher.ServerSelection, err = toInt32Err(oleutil.GetProperty(updateSearcherDisp, "ServerSelection")); err != nil {
+	serverSelection, err := toInt32Err(oleutil.GetProperty(updateSearcherDisp, "ServerSelection"))
+	if err != nil {
 		return nil, err
 	}
+	iUpdateSearcher.ServerSelection = ServerSelection(serverSelection)
 
 	if iUpdateSearcher.ServiceID, err = toStringErr(oleutil.GetProperty(updateSearcherDisp, "ServiceID")); err != nil {
 		return nil, err
@@ -63,6 +68,77 @@ func toIUpdateSearcher(updateSearcherDisp *ole.IDispatch) (*IUpdateSearcher, err
 	return iUpdateSearcher, nil
 }
 
+// SetCanAutomaticallyUpgradeService sets a value that indicates whether the Windows Update Agent is allowed to
+// upgrade itself when it searches against a managed service such as WSUS. If false and the managed server requires
+// a newer version of the agent, the search fails with an error instead of upgrading the client.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-put_canautomaticallyupgradeservice
+func (iUpdateSearcher *IUpdateSearcher) SetCanAutomaticallyUpgradeService(canAutomaticallyUpgradeService bool) error {
+	if _, err := oleutil.PutProperty(iUpdateSearcher.disp, "CanAutomaticallyUpgradeService", canAutomaticallyUpgradeService); err != nil {
+		return wrapError(err)
+	}
+	iUpdateSearcher.CanAutomaticallyUpgradeService = canAutomaticallyUpgradeService
+	return nil
+}
+
+// SetIncludePotentiallySupersededUpdates sets a value that indicates whether the search results include updates that are superseded by other updates in the search results.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher2-put_includepotentiallysupersededupdates
+func (iUpdateSearcher *IUpdateSearcher) SetIncludePotentiallySupersededUpdates(includePotentiallySupersededUpdates bool) error {
+	if _, err := oleutil.PutProperty(iUpdateSearcher.disp, "IncludePotentiallySupersededUpdates", includePotentiallySupersededUpdates); err != nil {
+		return wrapError(err)
+	}
+	iUpdateSearcher.IncludePotentiallySupersededUpdates = includePotentiallySupersededUpdates
+	return nil
+}
+
+// SetOnline sets a value that indicates whether the update agent goes online to search for updates.
+// Set it to false to perform an offline scan, typically together with ServerSelectionSsOthers and the
+// ServiceID of a scan package service registered from a wsusscn2.cab file.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-put_online
+func (iUpdateSearcher *IUpdateSearcher) SetOnline(online bool) error {
+	if _, err := oleutil.PutProperty(iUpdateSearcher.disp, "Online", online); err != nil {
+		return wrapError(err)
+	}
+	iUpdateSearcher.Online = online
+	return nil
+}
+
+// SearchScope gets the scope of the updates that are searched for.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher3-get_searchscope
+func (iUpdateSearcher *IUpdateSearcher) SearchScope() (SearchScope, error) {
+	searchScope, err := toInt32Err(oleutil.GetProperty(iUpdateSearcher.disp, "SearchScope"))
+	return SearchScope(searchScope), err
+}
+
+// SetSearchScope sets the scope of the updates that are searched for, e.g. SearchScopeCurrentUserOnly to search
+// for the per-user updates of the current user only. Searching the updates of other users requires elevation.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher3-put_searchscope
+func (iUpdateSearcher *IUpdateSearcher) SetSearchScope(searchScope SearchScope) error {
+	_, err := oleutil.PutProperty(iUpdateSearcher.disp, "SearchScope", int32(searchScope))
+	return wrapError(err)
+}
+
+// SetServerSelection sets a value that specifies the type of server to search against.
+// When serverSelection is ServerSelectionSsOthers, the service identified by ServiceID is searched,
+// so SetServiceID must be called as well.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-put_serverselection
+func (iUpdateSearcher *IUpdateSearcher) SetServerSelection(serverSelection ServerSelection) error {
+	if _, err := oleutil.PutProperty(iUpdateSearcher.disp, "ServerSelection", int32(serverSelection)); err != nil {
+		return wrapError(err)
+	}
+	iUpdateSearcher.ServerSelection = serverSelection
+	return nil
+}
+
+// SetServiceID sets a value that identifies the service to search against when ServerSelection is ServerSelectionSsOthers.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-put_serviceid
+func (iUpdateSearcher *IUpdateSearcher) SetServiceID(serviceID string) error {
+	if _, err := oleutil.PutProperty(iUpdateSearcher.disp, "ServiceID", serviceID); err != nil {
+		return wrapError(err)
+	}
+	iUpdateSearcher.ServiceID = serviceID
+	return nil
+}
+
 // Search performs a synchronous search for updates. The search uses the search options that are currently configured.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-search
 func (iUpdateSearcher *IUpdateSearcher) Search(criteria string) (*ISearchResult, error) {
@@ -70,7 +146,13 @@ func (iUpdateSearcher *IUpdateSearcher) Search(criteria string) (*ISearchResult,
 	if err != nil {
 		return nil, err
 	}
-	return toISearchResult(searchResultDisp)
+	return iUpdateSearcher.toISearchResult(searchResultDisp)
+}
+
+// EscapeString converts a string into a string literal that can be used as a value in a search criteria.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-escapestring
+func (iUpdateSearcher *IUpdateSearcher) EscapeString(unescaped string) (string, error) {
+	return toStringErr(oleutil.CallMethod(iUpdateSearcher.disp, "EscapeString", unescaped))
 }
 
 // QueryHistory synchronously queries the computer for the history of the update events.
@@ -85,8 +167,9 @@ func (iUpdateSearcher *IUpdateSearcher) QueryHistory(startIndex int32, count int
 
 // GetTotalHistoryCount returns the number of update events on the computer.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-gettotalhistorycount
-func (iUpdateSearcher *IUpdateSearcher) GetTotalHistoryCount() (int32, error) {
-	return toInt32Err(oleutil.CallMethod(iUpdateSearcher.disp, "GetTotalHistoryCount"))
+func (iUpdateSearcher *IUpdateSearcher) GetTotalHistoryCount() (int, error) {
+	count, err := toInt32Err(oleutil.CallMethod(iUpdateSearcher.disp, "GetTotalHistoryCount"))
+	return int(count), err
 }
 
 // QueryHistoryAll synchronously queries the computer for the history of all update events.
@@ -95,5 +178,141 @@ func (iUpdateSearcher *IUpdateSearcher) QueryHistoryAll() ([]*IUpdateHistoryEntr
 	if err != nil {
 		return nil, err
 	}
-	return iUpdateSearcher.QueryHistory(0, count)
+	if count == 0 {
+		return []*IUpdateHistoryEntry{}, nil
+	}
+	return iUpdateSearcher.QueryHistory(0, int32(count))
+}
+
+// HistoryIterator returns a function that reads the history of update events in pages of at most batch entries.
+// Each call returns the next page; once all entries are read, it returns an empty slice and a nil error.
+// The total number of entries is determined by GetTotalHistoryCount on the first call. batch must be positive.
+func (iUpdateSearcher *IUpdateSearcher) HistoryIterator(batch int) func() ([]*IUpdateHistoryEntry, error) {
+	var next, total int32
+	counted := false
+	return func() ([]*IUpdateHistoryEntry, error) {
+		if !counted {
+			count, err := iUpdateSearcher.GetTotalHistoryCount()
+			if err != nil {
+				return nil, err
+			}
+			total = int32(count)
+			counted = true
+		}
+		if next >= total || batch <= 0 {
+			return []*IUpdateHistoryEntry{}, nil
+		}
+
+		count := int32(batch)
+		if count > total-next {
+			count = total - next
+		}
+		entries, err := iUpdateSearcher.QueryHistory(next, count)
+		if err != nil {
+			return nil, err
+		}
+		next += count
+		return entries, nil
+	}
+}
+
+// SearchContext performs a search for updates like Search, but honors the cancellation and deadline of ctx.
+// The search is started with BeginSearch; if ctx is done before it completes, the search is aborted,
+// its COM objects are released and ctx.Err() is returned.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-beginsearch
+func (iUpdateSearcher *IUpdateSearcher) SearchContext(ctx context.Context, criteria string) (*ISearchResult, error) {
+	iSearchJob, err := iUpdateSearcher.beginSearch(ctx, criteria, nil)
+	if err != nil {
+		return nil, err
+	}
+	return iUpdateSearcher.EndSearch(iSearchJob)
+}
+
+// BeginSearch starts an asynchronous search for updates. The job must be passed to EndSearch, which waits for
+// the search to complete and returns its result. If onCompleted is not nil, it is called once the search is
+// completed, from within EndSearch. To cancel the search, call RequestAbort on the job.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-beginsearch
+func (iUpdateSearcher *IUpdateSearcher) BeginSearch(criteria string, onCompleted func(*ISearchJob)) (*ISearchJob, error) {
+	return iUpdateSearcher.beginSearch(context.Background(), criteria, onCompleted)
+}
+
+func (iUpdateSearcher *IUpdateSearcher) beginSearch(ctx context.Context, criteria string, onCompleted func(*ISearchJob)) (*ISearchJob, error) {
+	iSearchJob := &ISearchJob{}
+	iSearchJob.ctx, iSearchJob.cancel = context.WithCancel(ctx)
+	iSearchJob.onCompleted = newCallback(iidSearchCompletedCallback, func(job *ole.IDispatch, args *ole.IDispatch) {
+		if onCompleted != nil {
+			onCompleted(iSearchJob)
+		}
+	})
+
+	searchJobDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateSearcher.disp, "BeginSearch", criteria, iSearchJob.onCompleted, nil))
+	if err != nil {
+		releaseCallback(iSearchJob.onCompleted)
+		iSearchJob.cancel()
+		return nil, err
+	}
+	iSearchJob.disp = searchJobDisp
+	return iSearchJob, nil
+}
+
+// EndSearch waits for an asynchronous search started by BeginSearch to complete and returns its result.
+// It must be called from the thread that called BeginSearch. If the search was aborted with RequestAbort,
+// or the context passed to SearchContext is done first, the search is aborted and the context's error is returned.
+// The job is released and must not be used once EndSearch returns.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesearcher-endsearch
+func (iUpdateSearcher *IUpdateSearcher) EndSearch(searchJob *ISearchJob) (*ISearchResult, error) {
+	defer searchJob.disp.Release()
+	defer searchJob.CleanUp()
+
+	waitErr := waitForJob(searchJob.ctx, searchJob.disp)
+
+	searchResultDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateSearcher.disp, "EndSearch", searchJob.disp))
+	if waitErr != nil {
+		if searchResultDisp != nil {
+			searchResultDisp.Release()
+		}
+		return nil, waitErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return iUpdateSearcher.toISearchResult(searchResultDisp)
+}
+
+func (iUpdateSearcher *IUpdateSearcher) toISearchResult(searchResultDisp *ole.IDispatch) (*ISearchResult, error) {
+	iSearchResult, err := toISearchResult(searchResultDisp)
+	if err != nil {
+		return nil, err
+	}
+	iSearchResult.session = iUpdateSearcher.session
+	iSearchResult.searchSettings = &searchSettings{
+		online:          iUpdateSearcher.Online,
+		serverSelection: iUpdateSearcher.ServerSelection,
+		serviceID:       iUpdateSearcher.ServiceID,
+	}
+	return iSearchResult, nil
+}
+
+// searchSettings are the settings of the searcher that found an update. They are needed to find the same
+// update again in another session, e.g. one of a scan package service or of a specific server.
+type searchSettings struct {
+	online          bool
+	serverSelection ServerSelection
+	serviceID       string
+}
+
+// apply configures iUpdateSearcher with the settings.
+func (settings *searchSettings) apply(iUpdateSearcher *IUpdateSearcher) error {
+	if err := iUpdateSearcher.SetOnline(settings.online); err != nil {
+		return err
+	}
+	if err := iUpdateSearcher.SetServerSelection(settings.serverSelection); err != nil {
+		return err
+	}
+	if settings.serviceID != "" {
+		if err := iUpdateSearcher.SetServiceID(settings.serviceID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
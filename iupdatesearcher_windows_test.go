@@ -0,0 +1,88 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEscapeStringRoundTrip(t *testing.T) {
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer Uninitialize()
+
+	session, err := NewUpdateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	searcher, err := session.CreateUpdateSearcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := searcher.SetOnline(false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, unescaped := range []string{"KB5005565", "it's 100%", "''%'%%'"} {
+		escaped, err := searcher.EscapeString(unescaped)
+		if err != nil {
+			t.Fatalf("EscapeString(%q): %v", unescaped, err)
+		}
+		// An unescaped quote ends the literal early and makes WUA reject the criteria.
+		criteria := fmt.Sprintf("UpdateID='%s'", escaped)
+		result, err := searcher.Search(criteria)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", criteria, err)
+		}
+		updates, err := result.Updates()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(updates) != 0 {
+			t.Errorf("Search(%q) found %d updates, want 0", criteria, len(updates))
+		}
+	}
+}
+
+func TestGetTotalHistoryCount(t *testing.T) {
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer Uninitialize()
+
+	session, err := NewUpdateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	searcher, err := session.CreateUpdateSearcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := searcher.GetTotalHistoryCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count < 0 {
+		t.Errorf("GetTotalHistoryCount() = %d, want >= 0", count)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"time"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IUpdateService contains information about a service that is registered with Windows Update Agent (WUA) or with Automatic Updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdateservice
+type IUpdateService struct {
+	disp                 *ole.IDispatch
+	CanRegisterWithAU    bool
+	ExpirationDate       *time.Time // nil if the service does not expire
+	IsDefaultAUService   bool
+	IsManaged            bool
+	IsRegisteredWithAU   bool
+	IsScanPackageService bool
+	IssueDate            *time.Time
+	Name                 string
+	OffersWindowsUpdates bool
+	ServiceID            string
+	ServiceUrl           string
+}
+
+func toIUpdateServices(updateServicesDisp *ole.IDispatch) ([]*IUpdateService, error) {
+	count, err := toInt32Err(oleutil.GetProperty(updateServicesDisp, "Count"))
+	if err != nil {
+		return nil, err
+	}
+
+	updateServices := make([]*IUpdateService, 0, count)
+	for i := 0; i < int(count); i++ {
+		updateServiceDisp, err := toIDispatchErr(oleutil.GetProperty(updateServicesDisp, "Item", i))
+		if err != nil {
+			return nil, err
+		}
+
+		updateService, err := toIUpdateService(updateServiceDisp)
+		if err != nil {
+			return nil, err
+		}
+
+		updateServices = append(updateServices, updateService)
+	}
+	return updateServices, nil
+}
+
+func toIUpdateService(updateServiceDisp *ole.IDispatch) (*IUpdateService, error) {
+	var err error
+	iUpdateService := &IUpdateService{
+		disp: updateServiceDisp,
+	}
+
+	if iUpdateService.CanRegisterWithAU, err = toBoolErr(oleutil.GetProperty(updateServiceDisp, "CanRegisterWithAU")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.ExpirationDate, err = toTimeErr(oleutil.GetProperty(updateServiceDisp, "ExpirationDate")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.IsDefaultAUService, err = toBoolErr(oleutil.GetProperty(updateServiceDisp, "IsDefaultAUService")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.IsManaged, err = toBoolErr(oleutil.GetProperty(updateServiceDisp, "IsManaged")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.IsRegisteredWithAU, err = toBoolErr(oleutil.GetProperty(updateServiceDisp, "IsRegisteredWithAU")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.IsScanPackageService, err = toBoolErr(oleutil.GetProperty(updateServiceDisp, "IsScanPackageService")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.IssueDate, err = toTimeErr(oleutil.GetProperty(updateServiceDisp, "IssueDate")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.Name, err = toStringErr(oleutil.GetProperty(updateServiceDisp, "Name")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.OffersWindowsUpdates, err = toBoolErr(oleutil.GetProperty(updateServiceDisp, "OffersWindowsUpdates")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.ServiceID, err = toStringErr(oleutil.GetProperty(updateServiceDisp, "ServiceID")); err != nil {
+		return nil, err
+	}
+
+	if iUpdateService.ServiceUrl, err = toStringErr(oleutil.GetProperty(updateServiceDisp, "ServiceUrl")); err != nil {
+		return nil, err
+	}
+
+	return iUpdateService, nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// microsoftUpdateServiceID is the ID of the Microsoft Update service, which offers updates for
+// other Microsoft products such as Office and SQL Server in addition to Windows updates.
+const microsoftUpdateServiceID = "7971f918-a847-4430-9279-4a52d1efe18d"
+
+// IUpdateServiceManager adds or removes the registration of the update service with Windows Update Agent or Automatic Updates.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdateservicemanager
+type IUpdateServiceManager struct {
+	disp *ole.IDispatch
+}
+
+// NewUpdateServiceManager creates a new IUpdateServiceManager interface.
+func NewUpdateServiceManager() (*IUpdateServiceManager, error) {
+	unknown, err := oleutil.CreateObject("Microsoft.Update.ServiceManager")
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	disp, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &IUpdateServiceManager{
+		disp: disp,
+	}, nil
+}
+
+// Services gets the services that are registered with Windows Update Agent.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateservicemanager-get_services
+func (iUpdateServiceManager *IUpdateServiceManager) Services() ([]*IUpdateService, error) {
+	servicesDisp, err := toIDispatchErr(oleutil.GetProperty(iUpdateServiceManager.disp, "Services"))
+	if err != nil {
+		return nil, err
+	}
+	return toIUpdateServices(servicesDisp)
+}
+
+// AddService2 registers a service with Windows Update Agent, flags is a combination of AddServiceFlag values.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateservicemanager2-addservice2
+func (iUpdateServiceManager *IUpdateServiceManager) AddService2(serviceID string, flags AddServiceFlag, authorizationCabPath string) (*IUpdateServiceRegistration, error) {
+	registrationDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateServiceManager.disp, "AddService2", serviceID, int32(flags), authorizationCabPath))
+	if err != nil {
+		return nil, err
+	}
+	return toIUpdateServiceRegistration(registrationDisp)
+}
+
+// AddScanPackageService registers a scan package, such as the offline catalog wsusscn2.cab, as a service with Windows Update Agent.
+// flags is a combination of UpdateServiceOption values; without UpdateServiceOptionUsoNonVolatileService the service is removed
+// when the computer restarts.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateservicemanager-addscanpackageservice
+func (iUpdateServiceManager *IUpdateServiceManager) AddScanPackageService(serviceName string, scanFileLocation string, flags UpdateServiceOption) (*IUpdateService, error) {
+	serviceDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateServiceManager.disp, "AddScanPackageService", serviceName, scanFileLocation, int32(flags)))
+	if err != nil {
+		return nil, err
+	}
+	return toIUpdateService(serviceDisp)
+}
+
+// RemoveService removes a service registration from Windows Update Agent.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateservicemanager-removeservice
+func (iUpdateServiceManager *IUpdateServiceManager) RemoveService(serviceID string) error {
+	_, err := oleutil.CallMethod(iUpdateServiceManager.disp, "RemoveService", serviceID)
+	return wrapError(err)
+}
+
+// RegisterMicrosoftUpdate registers the Microsoft Update service with Windows Update Agent and
+// Automatic Updates, so that subsequent searches also find updates for other Microsoft products.
+// It returns an error wrapping ErrElevationRequired if the caller is not an administrator.
+func RegisterMicrosoftUpdate() error {
+	iUpdateServiceManager, err := NewUpdateServiceManager()
+	if err != nil {
+		return err
+	}
+	defer iUpdateServiceManager.disp.Release()
+
+	flags := AddServiceFlagAsfAllowPendingRegistration | AddServiceFlagAsfAllowOnlineRegistration | AddServiceFlagAsfRegisterServiceWithAU
+	if _, err := iUpdateServiceManager.AddService2(microsoftUpdateServiceID, flags, ""); err != nil {
+		return checkElevation(err)
+	}
+	return nil
+}
+
+// QueryServiceRegistration returns the registration of the service serviceID with Windows Update Agent.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateservicemanager2-queryserviceregistration
+func (iUpdateServiceManager *IUpdateServiceManager) QueryServiceRegistration(serviceID string) (*IUpdateServiceRegistration, error) {
+	registrationDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateServiceManager.disp, "QueryServiceRegistration", serviceID))
+	if err != nil {
+		return nil, err
+	}
+	return toIUpdateServiceRegistration(registrationDisp)
+}
+
+// DefaultAUService returns the service that Automatic Updates uses, i.e. the one whose IsDefaultAUService is true,
+// or nil if there is none.
+func (iUpdateServiceManager *IUpdateServiceManager) DefaultAUService() (*IUpdateService, error) {
+	services, err := iUpdateServiceManager.Services()
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		if service.IsDefaultAUService {
+			return service, nil
+		}
+	}
+	return nil, nil
+}
+
+// SetDefaultAUService registers the service serviceID with Windows Update Agent and Automatic Updates through
+// AddService2 and confirms that it became the service that Automatic Updates uses, e.g. to switch a computer between
+// a WSUS server and Windows Update. It returns an error wrapping ErrNotDefaultAUService if IsDefaultAUService of the
+// service is still false afterwards, and an error wrapping ErrElevationRequired if the caller is not an administrator.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateservicemanager2-addservice2
+func (iUpdateServiceManager *IUpdateServiceManager) SetDefaultAUService(serviceID string) error {
+	flags := AddServiceFlagAsfAllowPendingRegistration | AddServiceFlagAsfAllowOnlineRegistration | AddServiceFlagAsfRegisterServiceWithAU
+	if _, err := iUpdateServiceManager.AddService2(serviceID, flags, ""); err != nil {
+		return checkElevation(err)
+	}
+	service, err := iUpdateServiceManager.DefaultAUService()
+	if err != nil {
+		return err
+	}
+	if service == nil || !strings.EqualFold(service.ServiceID, serviceID) {
+		return fmt.Errorf("%w: %s", ErrNotDefaultAUService, serviceID)
+	}
+	return nil
+}
+
+// RegisterServiceWithAU registers the service serviceID, which must be registered with Windows Update Agent, with
+// Automatic Updates, so that Automatic Updates offers its updates. It returns an error wrapping ErrElevationRequired
+// if the caller is not an administrator.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateservicemanager-registerservicewithau
+func (iUpdateServiceManager *IUpdateServiceManager) RegisterServiceWithAU(serviceID string) error {
+	_, err := oleutil.CallMethod(iUpdateServiceManager.disp, "RegisterServiceWithAU", serviceID)
+	return checkElevation(wrapError(err))
+}
+
+// UnregisterServiceWithAU unregisters the service serviceID from Automatic Updates. It returns an error wrapping
+// ErrElevationRequired if the caller is not an administrator.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdateservicemanager-unregisterservicewithau
+func (iUpdateServiceManager *IUpdateServiceManager) UnregisterServiceWithAU(serviceID string) error {
+	_, err := oleutil.CallMethod(iUpdateServiceManager.disp, "UnregisterServiceWithAU", serviceID)
+	return checkElevation(wrapError(err))
+}
+
+// checkElevation wraps err with ErrElevationRequired if it is an access denied error.
+func checkElevation(err error) error {
+	if err != nil && hresultOf(err) == hresultEAccessDenied {
+		return fmt.Errorf("%w: %v", ErrElevationRequired, err)
+	}
+	return err
+}
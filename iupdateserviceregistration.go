@@ -0,0 +1,62 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IUpdateServiceRegistration contains information about the registration state of a service.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iupdateserviceregistration
+type IUpdateServiceRegistration struct {
+	disp                        *ole.IDispatch
+	IsPendingRegistrationWithAU bool
+	RegistrationState           UpdateServiceRegistrationState
+	Service                     *IUpdateService
+	ServiceID                   string
+}
+
+func toIUpdateServiceRegistration(updateServiceRegistrationDisp *ole.IDispatch) (*IUpdateServiceRegistration, error) {
+	var err error
+	iUpdateServiceRegistration := &IUpdateServiceRegistration{
+		disp: updateServiceRegistrationDisp,
+	}
+
+	if iUpdateServiceRegistration.IsPendingRegistrationWithAU, err = toBoolErr(oleutil.GetProperty(updateServiceRegistrationDisp, "IsPendingRegistrationWithAU")); err != nil {
+		return nil, err
+	}
+
+	registrationState, err := toInt32Err(oleutil.GetProperty(updateServiceRegistrationDisp, "RegistrationState"))
+	if err != nil {
+		return nil, err
+	}
+	iUpdateServiceRegistration.RegistrationState = UpdateServiceRegistrationState(registrationState)
+
+	serviceDisp, err := toIDispatchErr(oleutil.GetProperty(updateServiceRegistrationDisp, "Service"))
+	if err != nil {
+		return nil, err
+	}
+	if serviceDisp != nil {
+		if iUpdateServiceRegistration.Service, err = toIUpdateService(serviceDisp); err != nil {
+			return nil, err
+		}
+	}
+
+	if iUpdateServiceRegistration.ServiceID, err = toStringErr(oleutil.GetProperty(updateServiceRegistrationDisp, "ServiceID")); err != nil {
+		return nil, err
+	}
+
+	return iUpdateServiceRegistration, nil
+}
@@ -14,13 +14,19 @@ limitations under the License.
 package windowsupdate
 
 import (
-	"sync"
+	"fmt"
+	"runtime"
+	"time"
 
 	"github.com/go-ole/go-ole"
 	"github.com/go-ole/go-ole/oleutil"
 )
 
-var wuaSession sync.Mutex
+// Interface IDs of the newer versions of IUpdateSession.
+var (
+	iidUpdateSession2 = ole.NewGUID("{91CAF7B0-EB23-49ED-9937-C52D817F46F7}")
+	iidUpdateSession3 = ole.NewGUID("{918EFD1E-B5D8-4C90-8540-AEB9BDC56F9D}")
+)
 
 // IUpdateSession represents a session in which the caller can perform operations that involve updates.
 // For example, this interface represents sessions in which the caller performs a search, download, installation, or uninstallation operation.
@@ -30,6 +36,7 @@ type IUpdateSession struct {
 	ClientApplicationID string
 	ReadOnly            bool
 	WebProxy            *IWebProxy
+	onClose             func() // called once the session is released, nil unless set by NewUpdateSessionTimeout
 }
 
 func toIUpdateSession(updateSessionDisp *ole.IDispatch) (*IUpdateSession, error) {
@@ -60,37 +67,159 @@ func toIUpdateSession(updateSessionDisp *ole.IDispatch) (*IUpdateSession, error)
 }
 
 // NewUpdateSession creates a new IUpdateSession interface.
+// The calling goroutine is locked to its OS thread until Close is called, because the session
+// and every object retrieved from it belong to the COM apartment of that thread.
 func NewUpdateSession() (*IUpdateSession, error) {
-	wuaSession.Lock()
-	unknown, err := oleutil.CreateObject("Microsoft.Update.Session")
+	runtime.LockOSThread()
+	iUpdateSession, err := createUpdateSession()
 	if err != nil {
+		runtime.UnlockOSThread()
 		return nil, err
 	}
+	return iUpdateSession, nil
+}
+
+// createUpdateSession creates a session in the COM apartment of the calling thread.
+func createUpdateSession() (*IUpdateSession, error) {
+	unknown, err := oleutil.CreateObject("Microsoft.Update.Session")
+	if err != nil {
+		return nil, wrapError(err)
+	}
 	disp, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	iUpdateSession, err := toIUpdateSession(disp)
+	if err != nil {
+		disp.Release()
+		return nil, err
+	}
+	return iUpdateSession, nil
+}
+
+// NewUpdateSessionWithClientID creates a new IUpdateSession interface whose ClientApplicationID is set to clientApplicationID.
+// The ID is recorded in the update history, see IUpdateHistoryEntry.ClientApplicationID.
+func NewUpdateSessionWithClientID(clientApplicationID string) (*IUpdateSession, error) {
+	iUpdateSession, err := NewUpdateSession()
 	if err != nil {
 		return nil, err
 	}
-	return toIUpdateSession(disp)
+	if err := iUpdateSession.SetClientApplicationID(clientApplicationID); err != nil {
+		iUpdateSession.Close()
+		return nil, err
+	}
+	return iUpdateSession, nil
+}
+
+// NewUpdateSessionTimeout is like NewUpdateSession, but fails with an error wrapping ErrSessionTimeout if the
+// Windows Update service does not respond within timeout. The session is created in the multithreaded apartment
+// on a worker thread, which is kept until Close is called; a session that is created after timeout elapsed is closed
+// by the worker. The calling goroutine is locked to its OS thread until Close is called, as with NewUpdateSession.
+func NewUpdateSessionTimeout(timeout time.Duration) (*IUpdateSession, error) {
+	results := make(chan sessionResult)
+	abandoned := make(chan struct{})
+	go runSessionWorker(results, abandoned)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		runtime.LockOSThread()
+		return result.session, nil
+	case <-timer.C:
+		close(abandoned)
+		return nil, fmt.Errorf("%w after %v", ErrSessionTimeout, timeout)
+	}
+}
+
+type sessionResult struct {
+	session *IUpdateSession
+	err     error
+}
+
+// startWorkerSession initializes COM on the calling thread and creates a session there. The returned function
+// uninitializes COM again and must be called on the same thread once the session is released.
+var startWorkerSession = func() (*IUpdateSession, func(), error) {
+	if err := coInitializeEx(ole.COINIT_MULTITHREADED); err != nil {
+		return nil, nil, wrapError(err)
+	}
+	iUpdateSession, err := createUpdateSession()
+	if err != nil {
+		ole.CoUninitialize()
+		return nil, nil, err
+	}
+	return iUpdateSession, ole.CoUninitialize, nil
+}
+
+// runSessionWorker creates a session on a locked thread and sends it to results, unless abandoned is closed first,
+// in which case the session is released. The thread is kept until the session is released.
+func runSessionWorker(results chan<- sessionResult, abandoned <-chan struct{}) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	iUpdateSession, uninitialize, err := startWorkerSession()
+	if err != nil {
+		select {
+		case results <- sessionResult{err: err}:
+		case <-abandoned:
+		}
+		return
+	}
+	defer uninitialize()
+
+	released := make(chan struct{})
+	iUpdateSession.onClose = func() { close(released) }
+	select {
+	case results <- sessionResult{session: iUpdateSession}:
+		<-released
+	case <-abandoned:
+		iUpdateSession.release()
+	}
 }
 
-// CreateUpdateDownloader returns an IUpdateDownloader interface for this session.
+// CreateUpdateDownloader returns an IUpdateDownloader interface for this session. Its ClientApplicationID is set to that of the session.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession-createupdatedownloader
 func (iUpdateSession *IUpdateSession) CreateUpdateDownloader() (*IUpdateDownloader, error) {
 	updateDownloaderDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateSession.disp, "CreateUpdateDownloader"))
 	if err != nil {
 		return nil, err
 	}
-	return toIUpdateDownloader(updateDownloaderDisp)
+
+	iUpdateDownloader, err := toIUpdateDownloader(updateDownloaderDisp)
+	if err != nil {
+		return nil, err
+	}
+	iUpdateDownloader.session = iUpdateSession
+	if iUpdateSession.ClientApplicationID != "" {
+		if err := iUpdateDownloader.SetClientApplicationID(iUpdateSession.ClientApplicationID); err != nil {
+			return nil, err
+		}
+	}
+	return iUpdateDownloader, nil
 }
 
-// CreateUpdateInstaller returns an IUpdateInstaller interface for this session.
+// CreateUpdateInstaller returns an IUpdateInstaller interface for this session. Its ClientApplicationID is set to that of the session.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession-createupdateinstaller
 func (iUpdateSession *IUpdateSession) CreateUpdateInstaller() (*IUpdateInstaller, error) {
 	updateInstallerDisp, err := toIDispatchErr(oleutil.CallMethod(iUpdateSession.disp, "CreateUpdateInstaller"))
 	if err != nil {
 		return nil, err
 	}
-	return toIUpdateInstaller(updateInstallerDisp)
+
+	iUpdateInstaller, err := toIUpdateInstaller(updateInstallerDisp)
+	if err != nil {
+		return nil, err
+	}
+	iUpdateInstaller.session = iUpdateSession
+	if iUpdateSession.ClientApplicationID != "" {
+		if err := iUpdateInstaller.SetClientApplicationID(iUpdateSession.ClientApplicationID); err != nil {
+			return nil, err
+		}
+	}
+	return iUpdateInstaller, nil
 }
 
 // CreateUpdateSearcher returns an IUpdateSearcher interface for this session.
@@ -101,10 +230,213 @@ func (iUpdateSession *IUpdateSession) CreateUpdateSearcher() (*IUpdateSearcher,
 		return nil, err
 	}
 
-	return toIUpdateSearcher(updateSearcherDisp)
+	iUpdateSearcher, err := toIUpdateSearcher(updateSearcherDisp)
+	if err != nil {
+		return nil, err
+	}
+	iUpdateSearcher.session = iUpdateSession
+	return iUpdateSearcher, nil
+}
+
+// IsReadOnly reports whether the session is read-only. Objects retrieved from a read-only session cannot
+// modify updates or install them, and the methods of this package that would do so return ErrReadOnlySession.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession-get_readonly
+func (iUpdateSession *IUpdateSession) IsReadOnly() bool {
+	return iUpdateSession.ReadOnly
+}
+
+// SessionVersion returns the highest version of the IUpdateSession interfaces that the Windows Update Agent
+// supports, 1 to 3. Methods that require a newer version than the one supported return ErrNotSupported.
+func (iUpdateSession *IUpdateSession) SessionVersion() int {
+	if disp, err := iUpdateSession.disp.QueryInterface(iidUpdateSession3); err == nil {
+		disp.Release()
+		return 3
+	}
+	if disp, err := iUpdateSession.disp.QueryInterface(iidUpdateSession2); err == nil {
+		disp.Release()
+		return 2
+	}
+	return 1
+}
+
+// session3 returns the IUpdateSession3 interface of the session, which the caller must release,
+// or ErrNotSupported if the Windows Update Agent is too old to provide it.
+func (iUpdateSession *IUpdateSession) session3() (*ole.IDispatch, error) {
+	disp, err := iUpdateSession.disp.QueryInterface(iidUpdateSession3)
+	if err != nil {
+		return nil, ErrNotSupported
+	}
+	return disp, nil
 }
 
+// QueryHistory synchronously queries the computer for the history of the update events that match criteria,
+// e.g. "UpdateID='...'". It requires IUpdateSession3, see SessionVersion.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession3-queryhistory
+func (iUpdateSession *IUpdateSession) QueryHistory(criteria string, startIndex int32, count int32) ([]*IUpdateHistoryEntry, error) {
+	session3, err := iUpdateSession.session3()
+	if err != nil {
+		return nil, err
+	}
+	defer session3.Release()
+
+	updateHistoryEntriesDisp, err := toIDispatchErr(oleutil.CallMethod(session3, "QueryHistory", criteria, startIndex, count))
+	if err != nil {
+		return nil, err
+	}
+	return toIUpdateHistoryEntries(updateHistoryEntriesDisp)
+}
+
+// CreateUpdateServiceManager returns an IUpdateServiceManager interface that shares the settings of this session,
+// e.g. its WebProxy, unlike one created by NewUpdateServiceManager. It requires IUpdateSession3, see SessionVersion.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession3-createupdateservicemanager
+func (iUpdateSession *IUpdateSession) CreateUpdateServiceManager() (*IUpdateServiceManager, error) {
+	session3, err := iUpdateSession.session3()
+	if err != nil {
+		return nil, err
+	}
+	defer session3.Release()
+
+	updateServiceManagerDisp, err := toIDispatchErr(oleutil.CallMethod(session3, "CreateUpdateServiceManager"))
+	if err != nil {
+		return nil, err
+	}
+	return &IUpdateServiceManager{
+		disp: updateServiceManagerDisp,
+	}, nil
+}
+
+// checkWritable returns ErrReadOnlySession if session is a read-only session. A nil session,
+// e.g. that of an update not retrieved through a session, is not checked.
+func checkWritable(session *IUpdateSession) error {
+	if session != nil && session.IsReadOnly() {
+		return ErrReadOnlySession
+	}
+	return nil
+}
+
+// UserLocale gets the language identifier (LCID) of the locale that is used for the strings returned by WUA, such as update titles and descriptions.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession2-get_userlocale
+func (iUpdateSession *IUpdateSession) UserLocale() (uint32, error) {
+	return toUint32Err(oleutil.GetProperty(iUpdateSession.disp, "UserLocale"))
+}
+
+// SetUserLocale sets the language identifier (LCID) of the locale that is used for the strings returned by WUA,
+// e.g. 1033 for English (United States). It affects objects that are retrieved after the call.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession2-put_userlocale
+func (iUpdateSession *IUpdateSession) SetUserLocale(lcid uint32) error {
+	_, err := oleutil.PutProperty(iUpdateSession.disp, "UserLocale", lcid)
+	return wrapError(err)
+}
+
+// SetClientApplicationID sets the identifier of the current client application.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession-put_clientapplicationid
+func (iUpdateSession *IUpdateSession) SetClientApplicationID(clientApplicationID string) error {
+	if _, err := oleutil.PutProperty(iUpdateSession.disp, "ClientApplicationID", clientApplicationID); err != nil {
+		return wrapError(err)
+	}
+	iUpdateSession.ClientApplicationID = clientApplicationID
+	return nil
+}
+
+// SetWebProxy sets the proxy settings that are used to access the server.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iupdatesession-put_webproxy
+func (iUpdateSession *IUpdateSession) SetWebProxy(webProxy *IWebProxy) error {
+	if _, err := oleutil.PutProperty(iUpdateSession.disp, "WebProxy", webProxy.disp); err != nil {
+		return wrapError(err)
+	}
+	iUpdateSession.WebProxy = webProxy
+	return nil
+}
+
+// sessionSettings are the settings of a session that are taken over by the session of a background job,
+// see IUpdateDownloader.DownloadWithProgress. webProxy only holds the snapshot fields, without a COM object.
+type sessionSettings struct {
+	clientApplicationID string
+	webProxy            *IWebProxy
+}
+
+// settings returns the settings of the session with clientApplicationID, e.g. that of a downloader, in place of its own.
+func (iUpdateSession *IUpdateSession) settings(clientApplicationID string) sessionSettings {
+	settings := sessionSettings{
+		clientApplicationID: clientApplicationID,
+	}
+	if iUpdateSession != nil && iUpdateSession.WebProxy != nil {
+		webProxy := *iUpdateSession.WebProxy
+		webProxy.disp = nil
+		settings.webProxy = &webProxy
+	}
+	return settings
+}
+
+// newSession creates a session on the calling thread with the settings. The password of the proxy cannot
+// be read back from WUA, so it is not taken over.
+func (settings sessionSettings) newSession() (*IUpdateSession, error) {
+	iUpdateSession, err := NewUpdateSessionWithClientID(settings.clientApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.applyWebProxy(iUpdateSession); err != nil {
+		iUpdateSession.Close()
+		return nil, err
+	}
+	return iUpdateSession, nil
+}
+
+func (settings sessionSettings) applyWebProxy(iUpdateSession *IUpdateSession) error {
+	if settings.webProxy == nil || (settings.webProxy.Address == "" && !settings.webProxy.AutoDetect) {
+		return nil
+	}
+
+	webProxy, err := NewWebProxy()
+	if err != nil {
+		return err
+	}
+	if err := settings.configureWebProxy(webProxy); err != nil {
+		webProxy.disp.Release()
+		return err
+	}
+	return iUpdateSession.SetWebProxy(webProxy)
+}
+
+func (settings sessionSettings) configureWebProxy(webProxy *IWebProxy) error {
+	if settings.webProxy.Address != "" {
+		if err := webProxy.SetAddress(settings.webProxy.Address); err != nil {
+			return err
+		}
+	}
+	if err := webProxy.SetAutoDetect(settings.webProxy.AutoDetect); err != nil {
+		return err
+	}
+	if len(settings.webProxy.BypassList) > 0 {
+		if err := webProxy.SetBypassList(settings.webProxy.BypassList); err != nil {
+			return err
+		}
+	}
+	if err := webProxy.SetBypassProxyOnLocal(settings.webProxy.BypassProxyOnLocal); err != nil {
+		return err
+	}
+	if settings.webProxy.UserName != "" {
+		return webProxy.SetUserName(settings.webProxy.UserName)
+	}
+	return nil
+}
+
+// Close releases the session and unlocks the goroutine from its OS thread. It must be called
+// from the goroutine that created the session, and returns the remaining reference count.
 func (iUpdateSession *IUpdateSession) Close() int32 {
-	wuaSession.Unlock()
-	return iUpdateSession.disp.Release()
+	runtime.UnlockOSThread()
+	return iUpdateSession.release()
+}
+
+func (iUpdateSession *IUpdateSession) release() int32 {
+	var count int32
+	if iUpdateSession.disp != nil {
+		count = iUpdateSession.disp.Release()
+		iUpdateSession.disp = nil
+	}
+	if iUpdateSession.onClose != nil {
+		iUpdateSession.onClose()
+		iUpdateSession.onClose = nil
+	}
+	return count
 }
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubWorkerSession replaces the creation of the session in NewUpdateSessionTimeout with one that waits for proceed.
+// The returned channel is closed once the worker uninitialized its thread.
+func stubWorkerSession(t *testing.T, proceed <-chan struct{}, err error) (*IUpdateSession, <-chan struct{}) {
+	t.Helper()
+	session := &IUpdateSession{}
+	uninitialized := make(chan struct{})
+	start := startWorkerSession
+	startWorkerSession = func() (*IUpdateSession, func(), error) {
+		<-proceed
+		if err != nil {
+			close(uninitialized)
+			return nil, nil, err
+		}
+		return session, func() { close(uninitialized) }, nil
+	}
+	t.Cleanup(func() { startWorkerSession = start })
+	return session, uninitialized
+}
+
+func waitClosed(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s did not happen", what)
+	}
+}
+
+func TestNewUpdateSessionTimeout(t *testing.T) {
+	proceed := make(chan struct{})
+	close(proceed)
+	want, uninitialized := stubWorkerSession(t, proceed, nil)
+
+	session, err := NewUpdateSessionTimeout(5 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session != want {
+		t.Fatal("NewUpdateSessionTimeout did not return the session created by the worker")
+	}
+	select {
+	case <-uninitialized:
+		t.Fatal("the worker uninitialized its thread before the session was closed")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	session.Close()
+	waitClosed(t, uninitialized, "uninitializing the worker thread after Close")
+}
+
+func TestNewUpdateSessionTimeoutError(t *testing.T) {
+	proceed := make(chan struct{})
+	close(proceed)
+	errCreate := errors.New("creation failed")
+	stubWorkerSession(t, proceed, errCreate)
+
+	if _, err := NewUpdateSessionTimeout(5 * time.Second); err != errCreate {
+		t.Fatalf("NewUpdateSessionTimeout() error = %v, want %v", err, errCreate)
+	}
+}
+
+func TestNewUpdateSessionTimeoutElapsed(t *testing.T) {
+	proceed := make(chan struct{})
+	late, uninitialized := stubWorkerSession(t, proceed, nil)
+
+	session, err := NewUpdateSessionTimeout(10 * time.Millisecond)
+	if !errors.Is(err, ErrSessionTimeout) {
+		t.Fatalf("NewUpdateSessionTimeout() error = %v, want ErrSessionTimeout", err)
+	}
+	if session != nil {
+		t.Fatal("NewUpdateSessionTimeout returned a session after timing out")
+	}
+
+	// The creation completes after the timeout, so the worker must release the session itself.
+	close(proceed)
+	waitClosed(t, uninitialized, "uninitializing the worker thread after the late creation")
+	if late.onClose != nil {
+		t.Error("the session created after the timeout was not released")
+	}
+}
@@ -0,0 +1,107 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNewUpdateSessionAfterFailure(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// The goroutine is never unlocked, so its thread exits with it instead of returning to the pool
+		// with COM initialized.
+		runtime.LockOSThread()
+
+		// COM is not initialized on the new thread yet, so creating the session fails.
+		if session, err := NewUpdateSession(); err == nil {
+			session.Close()
+			t.Error("NewUpdateSession before Initialize succeeded, want an error")
+			return
+		}
+
+		if err := Initialize(); err != nil {
+			t.Error(err)
+			return
+		}
+		defer Uninitialize()
+
+		session, err := NewUpdateSession()
+		if err != nil {
+			t.Errorf("NewUpdateSession after a failed call: %v", err)
+			return
+		}
+		session.Close()
+	}()
+	<-done
+}
+
+func TestUserLocaleEnglishTitles(t *testing.T) {
+	const lcidEnglishUS = 1033
+
+	if err := Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer Uninitialize()
+
+	session, err := NewUpdateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	if err := session.SetUserLocale(lcidEnglishUS); err != nil {
+		t.Fatal(err)
+	}
+	lcid, err := session.UserLocale()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lcid != lcidEnglishUS {
+		t.Fatalf("UserLocale() = %d, want %d", lcid, lcidEnglishUS)
+	}
+
+	searcher, err := session.CreateUpdateSearcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := searcher.SetOnline(false); err != nil {
+		t.Fatal(err)
+	}
+	result, err := searcher.Search("IsInstalled=1 and Type='Software'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates, err := result.Updates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) == 0 {
+		t.Skip("no installed software updates to check the titles of")
+	}
+	// Titles of software updates such as "Security Intelligence Update for Microsoft Defender Antivirus" or
+	// "Cumulative Update for Windows" are translated in other locales.
+	for _, update := range updates {
+		if strings.Contains(update.Title, "Update") {
+			return
+		}
+	}
+	t.Errorf("no title of the %d installed software updates is in English, e.g. %q", len(updates), updates[0].Title)
+}
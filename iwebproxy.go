@@ -15,12 +15,13 @@ package windowsupdate
 
 import (
 	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
 )
 
 // IWebProxy contains the HTTP proxy settings.
 // https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iwebproxy
 type IWebProxy struct {
-	disp               *ole.Dispatch
+	disp               *ole.IDispatch
 	Address            string
 	AutoDetect         bool
 	BypassList         []string
@@ -30,6 +31,123 @@ type IWebProxy struct {
 }
 
 func toIWebProxy(webProxyDisp *ole.IDispatch) (*IWebProxy, error) {
-	// TODO
-	return nil, nil
+	var err error
+	iWebProxy := &IWebProxy{
+		disp: webProxyDisp,
+	}
+
+	if iWebProxy.Address, err = toStringErr(oleutil.GetProperty(webProxyDisp, "Address")); err != nil {
+		return nil, err
+	}
+
+	if iWebProxy.AutoDetect, err = toBoolErr(oleutil.GetProperty(webProxyDisp, "AutoDetect")); err != nil {
+		return nil, err
+	}
+
+	if iWebProxy.BypassList, err = iStringCollectionToStringArrayErr(toIDispatchErr(oleutil.GetProperty(webProxyDisp, "BypassList"))); err != nil {
+		return nil, err
+	}
+
+	if iWebProxy.BypassProxyOnLocal, err = toBoolErr(oleutil.GetProperty(webProxyDisp, "BypassProxyOnLocal")); err != nil {
+		return nil, err
+	}
+
+	if iWebProxy.ReadOnly, err = toBoolErr(oleutil.GetProperty(webProxyDisp, "ReadOnly")); err != nil {
+		return nil, err
+	}
+
+	if iWebProxy.UserName, err = toStringErr(oleutil.GetProperty(webProxyDisp, "UserName")); err != nil {
+		return nil, err
+	}
+
+	return iWebProxy, nil
+}
+
+// NewWebProxy creates a new IWebProxy interface, to be assigned to a session with IUpdateSession.SetWebProxy.
+func NewWebProxy() (*IWebProxy, error) {
+	unknown, err := oleutil.CreateObject("Microsoft.Update.WebProxy")
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	disp, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return toIWebProxy(disp)
+}
+
+// NewAutoDetectWebProxy creates a new IWebProxy interface that automatically detects the proxy settings, e.g. from a PAC file.
+func NewAutoDetectWebProxy() (*IWebProxy, error) {
+	iWebProxy, err := NewWebProxy()
+	if err != nil {
+		return nil, err
+	}
+	if err := iWebProxy.SetAutoDetect(true); err != nil {
+		iWebProxy.disp.Release()
+		return nil, err
+	}
+	return iWebProxy, nil
+}
+
+// SetAddress sets the address and the port of the proxy server, e.g. "proxy.example.com:8080".
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iwebproxy-put_address
+func (iWebProxy *IWebProxy) SetAddress(address string) error {
+	if _, err := oleutil.PutProperty(iWebProxy.disp, "Address", address); err != nil {
+		return wrapError(err)
+	}
+	iWebProxy.Address = address
+	return nil
+}
+
+// SetAutoDetect sets a Boolean value that indicates whether the proxy server is automatically detected.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iwebproxy-put_autodetect
+func (iWebProxy *IWebProxy) SetAutoDetect(autoDetect bool) error {
+	if _, err := oleutil.PutProperty(iWebProxy.disp, "AutoDetect", autoDetect); err != nil {
+		return wrapError(err)
+	}
+	iWebProxy.AutoDetect = autoDetect
+	return nil
+}
+
+// SetBypassList sets the addresses that do not use the proxy server.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iwebproxy-put_bypasslist
+func (iWebProxy *IWebProxy) SetBypassList(bypassList []string) error {
+	bypassListDisp, err := stringArrayToIStringCollection(bypassList)
+	if err != nil {
+		return err
+	}
+	defer bypassListDisp.Release()
+
+	if _, err := oleutil.PutProperty(iWebProxy.disp, "BypassList", bypassListDisp); err != nil {
+		return wrapError(err)
+	}
+	iWebProxy.BypassList = bypassList
+	return nil
+}
+
+// SetBypassProxyOnLocal sets a Boolean value that indicates whether local addresses bypass the proxy server.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iwebproxy-put_bypassproxyonlocal
+func (iWebProxy *IWebProxy) SetBypassProxyOnLocal(bypassProxyOnLocal bool) error {
+	if _, err := oleutil.PutProperty(iWebProxy.disp, "BypassProxyOnLocal", bypassProxyOnLocal); err != nil {
+		return wrapError(err)
+	}
+	iWebProxy.BypassProxyOnLocal = bypassProxyOnLocal
+	return nil
+}
+
+// SetUserName sets the user name to submit to the proxy server for authentication.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iwebproxy-put_username
+func (iWebProxy *IWebProxy) SetUserName(userName string) error {
+	if _, err := oleutil.PutProperty(iWebProxy.disp, "UserName", userName); err != nil {
+		return wrapError(err)
+	}
+	iWebProxy.UserName = userName
+	return nil
+}
+
+// SetPassword sets the password to submit to the proxy server for authentication. The password cannot be read back.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nf-wuapi-iwebproxy-setpassword
+func (iWebProxy *IWebProxy) SetPassword(password string) error {
+	_, err := oleutil.CallMethod(iWebProxy.disp, "SetPassword", password)
+	return wrapError(err)
 }
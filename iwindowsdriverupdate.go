@@ -0,0 +1,68 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"time"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+var iidWindowsDriverUpdate = ole.NewGUID("{B383CD1A-5CE9-4504-9F63-764B1236F191}")
+
+// IWindowsDriverUpdate contains the properties and methods that are available only from a Windows driver update.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iwindowsdriverupdate
+type IWindowsDriverUpdate struct {
+	disp               *ole.IDispatch
+	DriverClass        string
+	DriverHardwareID   string
+	DriverManufacturer string
+	DriverModel        string
+	DriverProvider     string
+	DriverVerDate      *time.Time
+}
+
+func toIWindowsDriverUpdate(windowsDriverUpdateDisp *ole.IDispatch) (*IWindowsDriverUpdate, error) {
+	var err error
+	iWindowsDriverUpdate := &IWindowsDriverUpdate{
+		disp: windowsDriverUpdateDisp,
+	}
+
+	if iWindowsDriverUpdate.DriverClass, err = toStringErr(oleutil.GetProperty(windowsDriverUpdateDisp, "DriverClass")); err != nil {
+		return nil, err
+	}
+
+	if iWindowsDriverUpdate.DriverHardwareID, err = toStringErr(oleutil.GetProperty(windowsDriverUpdateDisp, "DriverHardwareID")); err != nil {
+		return nil, err
+	}
+
+	if iWindowsDriverUpdate.DriverManufacturer, err = toStringErr(oleutil.GetProperty(windowsDriverUpdateDisp, "DriverManufacturer")); err != nil {
+		return nil, err
+	}
+
+	if iWindowsDriverUpdate.DriverModel, err = toStringErr(oleutil.GetProperty(windowsDriverUpdateDisp, "DriverModel")); err != nil {
+		return nil, err
+	}
+
+	if iWindowsDriverUpdate.DriverProvider, err = toStringErr(oleutil.GetProperty(windowsDriverUpdateDisp, "DriverProvider")); err != nil {
+		return nil, err
+	}
+
+	if iWindowsDriverUpdate.DriverVerDate, err = toTimeErr(oleutil.GetProperty(windowsDriverUpdateDisp, "DriverVerDate")); err != nil {
+		return nil, err
+	}
+
+	return iWindowsDriverUpdate, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// IWindowsUpdateAgentInfo retrieves version information about the Windows Update Agent.
+// The information is read when it is created, no COM reference is kept.
+// https://docs.microsoft.com/en-us/windows/win32/api/wuapi/nn-wuapi-iwindowsupdateagentinfo
+type IWindowsUpdateAgentInfo struct {
+	ApiMajorVersion      int32
+	ApiMinorVersion      int32
+	ProductVersionString string
+}
+
+func toIWindowsUpdateAgentInfo(windowsUpdateAgentInfoDisp *ole.IDispatch) (*IWindowsUpdateAgentInfo, error) {
+	var err error
+	iWindowsUpdateAgentInfo := &IWindowsUpdateAgentInfo{}
+
+	if iWindowsUpdateAgentInfo.ApiMajorVersion, err = toInt32Err(oleutil.CallMethod(windowsUpdateAgentInfoDisp, "GetInfo", "ApiMajorVersion")); err != nil {
+		return nil, err
+	}
+
+	if iWindowsUpdateAgentInfo.ApiMinorVersion, err = toInt32Err(oleutil.CallMethod(windowsUpdateAgentInfoDisp, "GetInfo", "ApiMinorVersion")); err != nil {
+		return nil, err
+	}
+
+	if iWindowsUpdateAgentInfo.ProductVersionString, err = toStringErr(oleutil.CallMethod(windowsUpdateAgentInfoDisp, "GetInfo", "ProductVersionString")); err != nil {
+		return nil, err
+	}
+
+	return iWindowsUpdateAgentInfo, nil
+}
+
+// NewWindowsUpdateAgentInfo creates a new IWindowsUpdateAgentInfo interface, which reports the API version
+// and the product version of the Windows Update Agent installed on the computer.
+func NewWindowsUpdateAgentInfo() (*IWindowsUpdateAgentInfo, error) {
+	unknown, err := oleutil.CreateObject("Microsoft.Update.AgentInfo")
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	disp, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer disp.Release()
+	return toIWindowsUpdateAgentInfo(disp)
+}
@@ -21,56 +21,63 @@ import (
 
 func toIDispatchErr(result *ole.VARIANT, err error) (*ole.IDispatch, error) {
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 	return variantToIDispatch(result), nil
 }
 
 func toInt64Err(result *ole.VARIANT, err error) (int64, error) {
 	if err != nil {
-		return 0, err
+		return 0, wrapError(err)
 	}
 	return variantToInt64(result), nil
 }
 
 func toInt32Err(result *ole.VARIANT, err error) (int32, error) {
 	if err != nil {
-		return 0, err
+		return 0, wrapError(err)
 	}
 	return variantToInt32(result), nil
 }
 
+func toUint32Err(result *ole.VARIANT, err error) (uint32, error) {
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return variantToUint32(result), nil
+}
+
 func toFloat64Err(result *ole.VARIANT, err error) (float64, error) {
 	if err != nil {
-		return 0, err
+		return 0, wrapError(err)
 	}
 	return variantToFloat64(result), nil
 }
 
 func toFloat32Err(result *ole.VARIANT, err error) (float32, error) {
 	if err != nil {
-		return 0, err
+		return 0, wrapError(err)
 	}
 	return variantToFloat32(result), nil
 }
 
 func toStringErr(result *ole.VARIANT, err error) (string, error) {
 	if err != nil {
-		return "", err
+		return "", wrapError(err)
 	}
 	return variantToString(result), nil
 }
 
 func toBoolErr(result *ole.VARIANT, err error) (bool, error) {
 	if err != nil {
-		return false, err
+		return false, wrapError(err)
 	}
 	return variantToBool(result), nil
 }
 
 func toTimeErr(result *ole.VARIANT, err error) (*time.Time, error) {
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 	return variantToTime(result), nil
 }
@@ -99,6 +106,16 @@ func variantToInt32(v *ole.VARIANT) int32 {
 	return value.(int32)
 }
 
+func variantToUint32(v *ole.VARIANT) uint32 {
+	switch value := v.Value().(type) {
+	case uint32:
+		return value
+	case int32:
+		return uint32(value)
+	}
+	return 0
+}
+
 func variantToFloat64(v *ole.VARIANT) float64 {
 	value := v.Value()
 	if value == nil {
@@ -132,10 +149,19 @@ func variantToBool(v *ole.VARIANT) bool {
 }
 
 func variantToTime(v *ole.VARIANT) *time.Time {
-	value := v.Value()
+	return valueToTime(v.Value())
+}
+
+func valueToTime(value interface{}) *time.Time {
 	if value == nil {
 		return nil
 	}
-	valueTime := value.(time.Time)
+	// Properties such as IUpdate.Deadline are VT_EMPTY or VT_NULL rather than VT_DATE when not set.
+	valueTime, ok := value.(time.Time)
+	if !ok {
+		return nil
+	}
+	// WUA reports dates in UTC.
+	valueTime = valueTime.UTC()
 	return &valueTime
 }
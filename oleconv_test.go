@@ -0,0 +1,60 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-ole/go-ole"
+)
+
+func TestValueToTime(t *testing.T) {
+	local := time.FixedZone("UTC+2", 2*60*60)
+	value := time.Date(2022, 3, 4, 7, 8, 9, 0, local)
+
+	got := valueToTime(value)
+	if got == nil {
+		t.Fatal("valueToTime(time.Time) = nil, want a time")
+	}
+	if got.Location() != time.UTC {
+		t.Errorf("valueToTime(time.Time) location = %v, want UTC", got.Location())
+	}
+	if want := time.Date(2022, 3, 4, 5, 8, 9, 0, time.UTC); !got.Equal(want) || *got != want {
+		t.Errorf("valueToTime(time.Time) = %v, want %v", *got, want)
+	}
+
+	// 1899-12-30 is day zero of the OLE automation date.
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if got := valueToTime(epoch); got == nil || *got != epoch {
+		t.Errorf("valueToTime(%v) = %v, want %v", epoch, got, epoch)
+	}
+}
+
+func TestValueToTimeNotSet(t *testing.T) {
+	for _, value := range []interface{}{nil, "2022-03-04", int32(0), float64(44624)} {
+		if got := valueToTime(value); got != nil {
+			t.Errorf("valueToTime(%#v) = %v, want nil", value, *got)
+		}
+	}
+}
+
+func TestVariantToTimeEmpty(t *testing.T) {
+	for _, vt := range []ole.VT{ole.VT_EMPTY, ole.VT_NULL} {
+		v := ole.NewVariant(vt, 0)
+		if got := variantToTime(&v); got != nil {
+			t.Errorf("variantToTime(VT %d) = %v, want nil", vt, *got)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"fmt"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// OperationResult is the outcome reported by the results of download, installation and uninstallation
+// operations, both overall and per update, and by the entries of the update history.
+type OperationResult struct {
+	HResult    int32
+	ResultCode OperationResultCode
+}
+
+func toOperationResult(disp *ole.IDispatch) (OperationResult, error) {
+	var err error
+	operationResult := OperationResult{}
+
+	if operationResult.HResult, err = toInt32Err(oleutil.GetProperty(disp, "HResult")); err != nil {
+		return operationResult, err
+	}
+
+	resultCode, err := toInt32Err(oleutil.GetProperty(disp, "ResultCode"))
+	if err != nil {
+		return operationResult, err
+	}
+	operationResult.ResultCode = OperationResultCode(resultCode)
+
+	return operationResult, nil
+}
+
+// Err returns nil if the operation succeeded. Otherwise it returns a *WUAError for HResult if it is set,
+// or an error naming ResultCode, e.g. for an operation that was aborted or succeeded with errors.
+func (operationResult OperationResult) Err() error {
+	if operationResult.ResultCode == OperationResultCodeOrcSucceeded {
+		return nil
+	}
+	if operationResult.HResult != 0 {
+		return &WUAError{HResult: uint32(operationResult.HResult), Message: DescribeHResult(operationResult.HResult)}
+	}
+	return fmt.Errorf("windowsupdate: the operation result is %s", operationResult.ResultCode)
+}
+
+// checkUpdateIndex returns an error if updateIndex is not a valid index into a result for count updates.
+func checkUpdateIndex(updateIndex int32, count int) error {
+	if updateIndex < 0 || int(updateIndex) >= count {
+		return fmt.Errorf("windowsupdate: index %d out of range [0,%d)", updateIndex, count)
+	}
+	return nil
+}
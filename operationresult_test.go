@@ -0,0 +1,54 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"testing"
+)
+
+func TestCheckUpdateIndex(t *testing.T) {
+	const count = 3
+	tests := []struct {
+		name        string
+		updateIndex int32
+		wantErr     bool
+	}{
+		{name: "negative", updateIndex: -1, wantErr: true},
+		{name: "count", updateIndex: count, wantErr: true},
+		{name: "last", updateIndex: count - 1, wantErr: false},
+		{name: "first", updateIndex: 0, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUpdateIndex(tt.updateIndex, count)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkUpdateIndex(%d, %d) = %v, wantErr %v", tt.updateIndex, count, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckUpdateIndexError(t *testing.T) {
+	err := checkUpdateIndex(5, 2)
+	if err == nil {
+		t.Fatal("checkUpdateIndex(5, 2) = nil, want an error")
+	}
+	if want := "windowsupdate: index 5 out of range [0,2)"; err.Error() != want {
+		t.Errorf("checkUpdateIndex(5, 2) = %q, want %q", err.Error(), want)
+	}
+
+	if err := checkUpdateIndex(0, 0); err == nil {
+		t.Error("checkUpdateIndex(0, 0) = nil, want an error for an empty result")
+	}
+}
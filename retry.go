@@ -0,0 +1,59 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"time"
+)
+
+// HRESULTs of the RPC and COM activation failures seen while the Windows Update service is starting or restarting.
+const (
+	hresultRPCServerUnavailable = 0x800706BA
+	hresultRPCServerTooBusy     = 0x800706BB
+	hresultRPCCallFailed        = 0x800706BE
+	hresultRPCDisconnected      = 0x80010108
+	hresultCOServerExecFailure  = 0x80080005
+)
+
+// isTransientHResult reports whether an operation that failed with hr may succeed if it is retried.
+func isTransientHResult(hr uint32) bool {
+	switch hr {
+	case wuEServiceStop, wuEOperationInProgress,
+		hresultRPCServerUnavailable, hresultRPCServerTooBusy, hresultRPCCallFailed, hresultRPCDisconnected, hresultCOServerExecFailure:
+		return true
+	}
+	return false
+}
+
+// WithRetry calls f up to attempts times until it returns nil, e.g. to run a search right after the computer starts.
+// f is only called again if its error is transient, such as WU_E_SERVICE_STOP or an RPC failure while the Windows
+// Update service restarts; any other error is returned at once. The wait before each new attempt starts at backoff
+// and doubles every time. The error of the last attempt is returned. f is always called at least once, even if
+// attempts is less than 1.
+func WithRetry(attempts int, backoff time.Duration, f func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if err = f(); err == nil || !isTransientHResult(hresultOf(err)) {
+			return err
+		}
+	}
+	return err
+}
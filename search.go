@@ -0,0 +1,284 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SearchOptions selects the updates searched for by the search helpers, in addition to the updates not being installed.
+type SearchOptions struct {
+	IncludeHidden   bool         // also find the updates that are hidden
+	OnlyRecommended bool         // only find the updates that are selected automatically on the Windows Update website
+	Types           []UpdateType // only find the updates of these types, any type if empty
+}
+
+// criteria returns the search criteria for the updates selected by searchOptions.
+func (searchOptions SearchOptions) criteria() string {
+	conditions := []string{"IsInstalled=0"}
+	if !searchOptions.IncludeHidden {
+		conditions = append(conditions, "IsHidden=0")
+	}
+	if searchOptions.OnlyRecommended {
+		conditions = append(conditions, "AutoSelectOnWebSites=1")
+	}
+	criteria := strings.Join(conditions, " and ")
+	if len(searchOptions.Types) == 0 {
+		return criteria
+	}
+
+	// Only the top level of the criteria can be a disjunction, so the conditions are repeated for each type.
+	disjuncts := make([]string, 0, len(searchOptions.Types))
+	for _, updateType := range searchOptions.Types {
+		disjuncts = append(disjuncts, fmt.Sprintf("%s and Type='%s'", criteria, updateType))
+	}
+	return strings.Join(disjuncts, " or ")
+}
+
+// SearchUpdates searches for the updates that are applicable to the computer, are not installed and are selected by options.
+func SearchUpdates(session *IUpdateSession, options SearchOptions) ([]*IUpdate, error) {
+	searcher, err := session.CreateUpdateSearcher()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := searcher.Search(options.criteria())
+	if err != nil {
+		return nil, err
+	}
+	return result.Updates()
+}
+
+// SearchSoftwareUpdates searches for the software updates that are applicable to the computer
+// and are neither installed nor hidden.
+func SearchSoftwareUpdates(session *IUpdateSession) ([]*IUpdate, error) {
+	return SearchUpdates(session, SearchOptions{Types: []UpdateType{UpdateTypeUtSoftware}})
+}
+
+// FilterByKB returns the updates whose KBArticleIDs contain kb. kb may be given
+// with or without the "KB" prefix, e.g. "5034122" and "KB5034122" are equivalent.
+func FilterByKB(updates []*IUpdate, kb string) []*IUpdate {
+	kb = normalizeKB(kb)
+	filtered := []*IUpdate{}
+	for _, update := range updates {
+		for _, kbArticleID := range update.KBArticleIDs {
+			if normalizeKB(kbArticleID) == kb {
+				filtered = append(filtered, update)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func normalizeKB(kb string) string {
+	kb = strings.TrimSpace(kb)
+	if len(kb) >= 2 && strings.EqualFold(kb[:2], "KB") {
+		kb = kb[2:]
+	}
+	return kb
+}
+
+// FindUpdatesByKB searches for the applicable updates that are not installed, are selected by options and belong to
+// the KB article kb, see FilterByKB.
+func (iUpdateSession *IUpdateSession) FindUpdatesByKB(kb string, options SearchOptions) ([]*IUpdate, error) {
+	updates, err := SearchUpdates(iUpdateSession, options)
+	if err != nil {
+		return nil, err
+	}
+	return FilterByKB(updates, kb), nil
+}
+
+// FindUpdateByID searches for the update with the UpdateID updateID and the revision number revision.
+// If revision is 0, any revision matches. ErrUpdateNotFound is returned if there is no such update.
+func (iUpdateSession *IUpdateSession) FindUpdateByID(updateID string, revision int32) (*IUpdate, error) {
+	searcher, err := iUpdateSession.CreateUpdateSearcher()
+	if err != nil {
+		return nil, err
+	}
+
+	escapedUpdateID, err := searcher.EscapeString(updateID)
+	if err != nil {
+		return nil, err
+	}
+	criteria := fmt.Sprintf("UpdateID='%s'", escapedUpdateID)
+	if revision != 0 {
+		criteria += fmt.Sprintf(" and RevisionNumber=%d", revision)
+	}
+
+	result, err := searcher.Search(criteria)
+	if err != nil {
+		return nil, err
+	}
+	resultUpdates, err := result.Updates()
+	if err != nil {
+		return nil, err
+	}
+	for _, update := range resultUpdates {
+		if update.Identity == nil || !strings.EqualFold(update.Identity.UpdateID, updateID) {
+			continue
+		}
+		if revision == 0 || update.Identity.RevisionNumber == revision {
+			return update, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUpdateNotFound, updateID)
+}
+
+// findUpdates searches for the updates with the identities of updates, and returns them in the same order.
+// It is used to get the updates of another session. The updates found by the same search are searched for
+// again with the settings of the searcher that found them, see searchSettings. ErrUpdateNotFound is returned
+// if any of them is missing.
+func (iUpdateSession *IUpdateSession) findUpdates(updates []*IUpdate) ([]*IUpdate, error) {
+	groups := map[*searchSettings][]*IUpdate{}
+	order := []*searchSettings{}
+	for _, update := range updates {
+		if update.Identity == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUpdateNotFound, update.Title)
+		}
+		if _, ok := groups[update.searchSettings]; !ok {
+			order = append(order, update.searchSettings)
+		}
+		groups[update.searchSettings] = append(groups[update.searchSettings], update)
+	}
+
+	found := map[string]*IUpdate{}
+	for _, settings := range order {
+		if err := iUpdateSession.findUpdatesWith(settings, groups[settings], found); err != nil {
+			return nil, err
+		}
+	}
+
+	sessionUpdates := make([]*IUpdate, 0, len(updates))
+	for _, update := range updates {
+		sessionUpdate, ok := found[identityKey(update.Identity)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUpdateNotFound, update.Identity.UpdateID)
+		}
+		sessionUpdates = append(sessionUpdates, sessionUpdate)
+	}
+	return sessionUpdates, nil
+}
+
+// findUpdatesWith searches for updates with a searcher configured with settings, or with the default
+// settings if settings is nil, and adds the updates found to found, see identityKey.
+func (iUpdateSession *IUpdateSession) findUpdatesWith(settings *searchSettings, updates []*IUpdate, found map[string]*IUpdate) error {
+	searcher, err := iUpdateSession.CreateUpdateSearcher()
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		if err := settings.apply(searcher); err != nil {
+			return err
+		}
+	}
+
+	criteria := make([]string, 0, len(updates))
+	for _, update := range updates {
+		escapedUpdateID, err := searcher.EscapeString(update.Identity.UpdateID)
+		if err != nil {
+			return err
+		}
+		criteria = append(criteria, fmt.Sprintf("(UpdateID='%s' and RevisionNumber=%d)", escapedUpdateID, update.Identity.RevisionNumber))
+	}
+
+	result, err := searcher.Search(strings.Join(criteria, " or "))
+	if err != nil {
+		return err
+	}
+	resultUpdates, err := result.Updates()
+	if err != nil {
+		return err
+	}
+	for _, update := range resultUpdates {
+		if update.Identity != nil {
+			found[identityKey(update.Identity)] = update
+		}
+	}
+	return nil
+}
+
+// identityKey returns a key that is equal for the same revision of the same update.
+func identityKey(identity *IUpdateIdentity) string {
+	return fmt.Sprintf("%s/%d", strings.ToLower(identity.UpdateID), identity.RevisionNumber)
+}
+
+// connectivityCriteria matches no update, so a search with it only checks that the update server can be reached.
+const connectivityCriteria = "UpdateID='00000000-0000-0000-0000-000000000000'"
+
+// CheckConnectivity performs an online search that matches no update, to check that the update server can be reached
+// before a long search. If it cannot, an error matching ErrNoConnection with errors.Is is returned.
+func (iUpdateSession *IUpdateSession) CheckConnectivity() error {
+	searcher, err := iUpdateSession.CreateUpdateSearcher()
+	if err != nil {
+		return err
+	}
+	if err := searcher.SetOnline(true); err != nil {
+		return err
+	}
+
+	if _, err := searcher.Search(connectivityCriteria); err != nil {
+		if hr := hresultOf(err); hr != wuENoConnection && isConnectivityHResult(hr) {
+			return fmt.Errorf("%w: %v", ErrNoConnection, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// offlineScanServiceName is the name of the temporary scan package service registered by ScanOffline.
+const offlineScanServiceName = "windowsupdate offline scan"
+
+// ScanOffline searches for the applicable updates that are not installed and are selected by options, using the offline catalog at cabPath,
+// e.g. a downloaded wsusscn2.cab, instead of an update server. The catalog is registered as a scan package
+// service for the duration of the search and removed afterward; if removing it fails after a successful search, that
+// error is returned instead of the updates.
+func ScanOffline(session *IUpdateSession, cabPath string, options SearchOptions) (updates []*IUpdate, err error) {
+	serviceManager, err := NewUpdateServiceManager()
+	if err != nil {
+		return nil, err
+	}
+	defer serviceManager.disp.Release()
+
+	service, err := serviceManager.AddScanPackageService(offlineScanServiceName, cabPath, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if removeErr := serviceManager.RemoveService(service.ServiceID); removeErr != nil && err == nil {
+			updates, err = nil, fmt.Errorf("windowsupdate: removing the offline scan service %s: %w", service.ServiceID, removeErr)
+		}
+	}()
+
+	searcher, err := session.CreateUpdateSearcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := searcher.SetServerSelection(ServerSelectionSsOthers); err != nil {
+		return nil, err
+	}
+	if err := searcher.SetServiceID(service.ServiceID); err != nil {
+		return nil, err
+	}
+	if err := searcher.SetOnline(false); err != nil {
+		return nil, err
+	}
+
+	result, err := searcher.Search(options.criteria())
+	if err != nil {
+		return nil, err
+	}
+	return result.Updates()
+}
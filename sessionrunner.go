@@ -0,0 +1,96 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"sync"
+)
+
+// SessionRunner owns an IUpdateSession on a dedicated OS thread and runs functions on that thread, so that
+// the session can be used from any goroutine. Objects retrieved from the session belong to the thread as
+// well and must only be used within the functions passed to Do.
+type SessionRunner struct {
+	calls     chan func(*IUpdateSession)
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSessionRunner starts a goroutine locked to its OS thread, initializes COM on it and creates the session.
+// Close must be called to release the session and stop the goroutine.
+func NewSessionRunner() (*SessionRunner, error) {
+	sessionRunner := &SessionRunner{
+		calls:   make(chan func(*IUpdateSession)),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	started := make(chan error, 1)
+	go sessionRunner.run(started)
+	if err := <-started; err != nil {
+		return nil, err
+	}
+	return sessionRunner, nil
+}
+
+func (sessionRunner *SessionRunner) run(started chan<- error) {
+	defer close(sessionRunner.stopped)
+
+	if err := Initialize(); err != nil {
+		started <- err
+		return
+	}
+	defer Uninitialize()
+
+	session, err := NewUpdateSession()
+	if err != nil {
+		started <- err
+		return
+	}
+	defer session.Close()
+	started <- nil
+
+	for {
+		select {
+		case call := <-sessionRunner.calls:
+			call(session)
+		case <-sessionRunner.done:
+			return
+		}
+	}
+}
+
+// Do runs f with the session on the thread of the runner and returns its error. Calls are run one at a time,
+// in the order they are made. ErrSessionRunnerClosed is returned if the runner is closed.
+func (sessionRunner *SessionRunner) Do(f func(*IUpdateSession) error) error {
+	result := make(chan error, 1)
+	call := func(session *IUpdateSession) {
+		result <- f(session)
+	}
+
+	select {
+	case sessionRunner.calls <- call:
+	case <-sessionRunner.done:
+		return ErrSessionRunnerClosed
+	}
+	return <-result
+}
+
+// Close waits for the current call of Do to return, then closes the session and stops the runner's goroutine.
+func (sessionRunner *SessionRunner) Close() {
+	sessionRunner.closeOnce.Do(func() {
+		close(sessionRunner.done)
+	})
+	<-sessionRunner.stopped
+}
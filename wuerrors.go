@@ -0,0 +1,128 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-ole/go-ole"
+)
+
+// WUAError is a COM error returned by the Windows Update Agent. Errors returned by this package
+// for failed COM calls are of type *WUAError and can be matched against the Err* values below
+// with errors.Is, which compares the HRESULT.
+type WUAError struct {
+	HResult uint32
+	Message string
+	Err     error // the underlying *ole.OleError, nil for the Err* values
+}
+
+func (e *WUAError) Error() string {
+	return fmt.Sprintf("windowsupdate: %s (0x%08X)", e.Message, e.HResult)
+}
+
+// Unwrap returns the underlying *ole.OleError.
+func (e *WUAError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a *WUAError with the same HRESULT.
+func (e *WUAError) Is(target error) bool {
+	t, ok := target.(*WUAError)
+	return ok && t.HResult == e.HResult
+}
+
+func newWUAErrorValue(hr uint32) *WUAError {
+	return &WUAError{HResult: hr, Message: wuaErrorMessages[hr]}
+}
+
+// Errors for common WUA error codes, to be matched with errors.Is.
+var (
+	ErrNotInitialized      = newWUAErrorValue(wuENotInitialized)
+	ErrOperationInProgress = newWUAErrorValue(wuEOperationInProgress)
+	ErrCallCancelled       = newWUAErrorValue(wuECallCancelled)
+	ErrInstallNotAllowed   = newWUAErrorValue(wuEInstallNotAllowed)
+	ErrServiceStop         = newWUAErrorValue(wuEServiceStop)
+	ErrNoConnection        = newWUAErrorValue(wuENoConnection)
+	ErrTimeOut             = newWUAErrorValue(wuETimeOut)
+	ErrAllUpdatesFailed    = newWUAErrorValue(wuEAllUpdatesFailed)
+	ErrNoUpdate            = newWUAErrorValue(wuENoUpdate)
+	ErrLegacyServer        = newWUAErrorValue(wuELegacyServer)
+	ErrWUDisabled          = newWUAErrorValue(wuEWUDisabled)
+	ErrInvalidCriteria     = newWUAErrorValue(wuEInvalidCriteria)
+	ErrDownloadFailed      = newWUAErrorValue(wuEDownloadFailed)
+	ErrNotSupported        = newWUAErrorValue(wuENotSupported)
+)
+
+// wrapError converts a COM error into a *WUAError. Other errors, including errors
+// that are already a *WUAError, are returned unchanged.
+func wrapError(err error) error {
+	oleErr, ok := err.(*ole.OleError)
+	if !ok {
+		return err
+	}
+
+	hr := uint32(oleErr.Code())
+	// Errors raised by WUA through IDispatch are reported as DISP_E_EXCEPTION, the
+	// HRESULT of the failed operation is then the scode of the exception info.
+	if hr == hresultDispEException {
+		if excepInfo, ok := oleErr.SubError().(ole.EXCEPINFO); ok && excepInfo.SCODE() != 0 {
+			hr = excepInfo.SCODE()
+		}
+	}
+
+	message, ok := wuaErrorMessages[hr]
+	if !ok {
+		if message = oleErr.Description(); message == "" {
+			message = oleErr.String()
+		}
+	}
+	return &WUAError{HResult: hr, Message: message, Err: oleErr}
+}
+
+// hresultOf returns the HRESULT of a COM error, or 0 if err is not a COM error.
+func hresultOf(err error) uint32 {
+	var wuaErr *WUAError
+	if errors.As(err, &wuaErr) {
+		return wuaErr.HResult
+	}
+	return 0
+}
+
+// isConnectivityHResult reports whether hr means that the update server could not be reached: WU_E_NO_CONNECTION,
+// an error of the WUA protocol talker (WU_E_PT_*) or a WinHTTP error such as a name that could not be resolved.
+func isConnectivityHResult(hr uint32) bool {
+	switch {
+	case hr == wuENoConnection:
+		return true
+	case hr >= 0x80244000 && hr <= 0x80244FFF:
+		return true
+	case hr >= 0x80072EE1 && hr <= 0x80072F9A:
+		return true
+	}
+	return false
+}
+
+// DescribeHResult returns a description of hr, e.g. an HResult stored from an earlier installation.
+// WUA error codes are described from wuerror.h, other codes by the system message table.
+func DescribeHResult(hr int32) string {
+	if message, ok := wuaErrorMessages[uint32(hr)]; ok {
+		return message
+	}
+	if message := formatMessage(uint32(hr)); message != "" {
+		return message
+	}
+	return fmt.Sprintf("unknown error 0x%08X", uint32(hr))
+}
@@ -0,0 +1,32 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+import (
+	"strings"
+	"syscall"
+)
+
+// formatMessage returns the system description of hr, or "" if there is none.
+func formatMessage(hr uint32) string {
+	buf := make([]uint16, 512)
+	n, err := syscall.FormatMessage(syscall.FORMAT_MESSAGE_FROM_SYSTEM|syscall.FORMAT_MESSAGE_IGNORE_INSERTS, 0, hr, 0, buf, nil)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(syscall.UTF16ToString(buf[:n]), "\r\n. ")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 Zheng Dayu
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package windowsupdate
+
+// Common WUA error codes, see wuerror.h.
+// https://docs.microsoft.com/en-us/windows/deployment/update/windows-update-error-reference
+const (
+	wuENoService              = 0x80240001
+	wuEMaxCapacityReached     = 0x80240002
+	wuEUnknownID              = 0x80240003
+	wuENotInitialized         = 0x80240004
+	wuEInvalidIndex           = 0x80240007
+	wuEItemNotFound           = 0x80240008
+	wuEOperationInProgress    = 0x80240009
+	wuECouldNotCancel         = 0x8024000A
+	wuECallCancelled          = 0x8024000B
+	wuENoop                   = 0x8024000C
+	wuEInstallNotAllowed      = 0x80240016
+	wuENotApplicable          = 0x80240017
+	wuENoUserToken            = 0x80240018
+	wuEExclusiveInstall       = 0x80240019
+	wuEPolicyNotSet           = 0x8024001A
+	wuESelfUpdateInProgress   = 0x8024001B
+	wuEInvalidUpdate          = 0x8024001D
+	wuEServiceStop            = 0x8024001E
+	wuENoConnection           = 0x8024001F
+	wuENoInteractiveUser      = 0x80240020
+	wuETimeOut                = 0x80240021
+	wuEAllUpdatesFailed       = 0x80240022
+	wuEEulasDeclined          = 0x80240023
+	wuENoUpdate               = 0x80240024
+	wuEUserAccessDisabled     = 0x80240025
+	wuEInvalidUpdateType      = 0x80240026
+	wuEURLTooLong             = 0x80240027
+	wuEUninstallNotAllowed    = 0x80240028
+	wuEInvalidProductLicense  = 0x80240029
+	wuEMissingHandler         = 0x8024002A
+	wuELegacyServer           = 0x8024002B
+	wuEBinSourceAbsent        = 0x8024002C
+	wuESourceAbsent           = 0x8024002D
+	wuEWUDisabled             = 0x8024002E
+	wuECallCancelledByPolicy  = 0x8024002F
+	wuEInvalidProxyServer     = 0x80240030
+	wuEInvalidFile            = 0x80240031
+	wuEInvalidCriteria        = 0x80240032
+	wuEEulaUnavailable        = 0x80240033
+	wuEDownloadFailed         = 0x80240034
+	wuEUpdateNotProcessed     = 0x80240035
+	wuEInvalidOperation       = 0x80240036
+	wuENotSupported           = 0x80240037
+	wuETooManyResync          = 0x80240039
+	wuENoServerCoreSupport    = 0x80240040
+	wuESysprepInProgress      = 0x80240041
+	wuEUnknownService         = 0x80240042
+	wuENoUISupport            = 0x80240043
+	wuEPerMachineAccessDenied = 0x80240044
+	wuEUnexpected             = 0x80240FFF
+)
+
+// wuaErrorMessages maps WUA error codes to the descriptions of wuerror.h.
+var wuaErrorMessages = map[uint32]string{
+	wuENoService:              "WUA was unable to provide the service",
+	wuEMaxCapacityReached:     "the maximum capacity of the service was exceeded",
+	wuEUnknownID:              "WUA cannot find an ID",
+	wuENotInitialized:         "the object could not be initialized",
+	wuEInvalidIndex:           "the index to a collection was invalid",
+	wuEItemNotFound:           "the key for the item queried could not be found",
+	wuEOperationInProgress:    "another conflicting operation was in progress",
+	wuECouldNotCancel:         "cancellation of the operation was not allowed",
+	wuECallCancelled:          "the operation was cancelled",
+	wuENoop:                   "no operation was required",
+	wuEInstallNotAllowed:      "the installation is not allowed, for example because an installation or uninstallation is already in progress",
+	wuENotApplicable:          "the operation was not performed because there are no applicable updates",
+	wuENoUserToken:            "the operation failed because a required user token is missing",
+	wuEExclusiveInstall:       "an exclusive update cannot be installed with other updates at the same time",
+	wuEPolicyNotSet:           "a policy value was not set",
+	wuESelfUpdateInProgress:   "the operation could not be performed because the Windows Update Agent is self-updating",
+	wuEInvalidUpdate:          "an update contains invalid metadata",
+	wuEServiceStop:            "the operation did not complete because the service or system was being shut down",
+	wuENoConnection:           "the operation did not complete because the network connection was unavailable",
+	wuENoInteractiveUser:      "the operation did not complete because there is no logged-on interactive user",
+	wuETimeOut:                "the operation did not complete because it timed out",
+	wuEAllUpdatesFailed:       "the operation failed for all the updates",
+	wuEEulasDeclined:          "the license terms for all updates were declined",
+	wuENoUpdate:               "there are no updates",
+	wuEUserAccessDisabled:     "group policy settings prevented access to Windows Update",
+	wuEInvalidUpdateType:      "the type of update is invalid",
+	wuEURLTooLong:             "the URL exceeded the maximum length",
+	wuEUninstallNotAllowed:    "the update could not be uninstalled because the request did not originate from a WSUS server",
+	wuEInvalidProductLicense:  "search may have missed some updates because there is an unlicensed application on the system",
+	wuEMissingHandler:         "a component required to detect applicable updates was missing",
+	wuELegacyServer:           "an operation did not complete because it requires a newer version of server",
+	wuEBinSourceAbsent:        "a delta-compressed update could not be installed because it required the source",
+	wuESourceAbsent:           "a full-file update could not be installed because it required the source",
+	wuEWUDisabled:             "access to an unmanaged server is not allowed",
+	wuECallCancelledByPolicy:  "the operation did not complete because the DisableWindowsUpdateAccess policy was set",
+	wuEInvalidProxyServer:     "the format of the proxy list was invalid",
+	wuEInvalidFile:            "the file is in the wrong format",
+	wuEInvalidCriteria:        "the search criteria string was invalid",
+	wuEEulaUnavailable:        "license terms could not be downloaded",
+	wuEDownloadFailed:         "the update failed to download",
+	wuEUpdateNotProcessed:     "the update was not processed",
+	wuEInvalidOperation:       "the object's current state did not allow the operation",
+	wuENotSupported:           "the functionality for the operation is not supported",
+	wuETooManyResync:          "the agent was asked to resynchronize too many times",
+	wuENoServerCoreSupport:    "the WUA API is not available on Server Core installations",
+	wuESysprepInProgress:      "the service is not available while sysprep is running",
+	wuEUnknownService:         "the update service is no longer registered with AU",
+	wuENoUISupport:            "there is no support for the WUA user interface",
+	wuEPerMachineAccessDenied: "only administrators can perform this operation on per-machine updates",
+	wuEUnexpected:             "an operation failed due to reasons not covered by another error code",
+}